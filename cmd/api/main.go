@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hoanghnt/TaskManagementAPI/internal/config"
 	"github.com/hoanghnt/TaskManagementAPI/internal/database"
 	"github.com/hoanghnt/TaskManagementAPI/internal/handlers"
+	"github.com/hoanghnt/TaskManagementAPI/internal/lockout"
+	"github.com/hoanghnt/TaskManagementAPI/internal/mailer"
 	"github.com/hoanghnt/TaskManagementAPI/internal/middleware"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/oidc"
 	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
 	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/storage"
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 
 	_ "github.com/hoanghnt/TaskManagementAPI/docs"
@@ -37,7 +44,12 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and JWT token or API key (tm_...).
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description Personal API key (tm_...).
 
 func main() {
 	// Load configuration
@@ -60,32 +72,175 @@ func main() {
 	}
 
 	// Initialize JWT
-	utils.InitJWT(cfg.JWT.Secret)
-	log.Println("✅ JWT initialized")
+	if cfg.JWT.PrivateKeyFile != "" {
+		if err := utils.InitJWTKeys(cfg.JWT.PrivateKeyFile, cfg.JWT.PublicKeyFiles); err != nil {
+			log.Fatalf("❌ Failed to initialize JWT: %v", err)
+		}
+	} else {
+		utils.InitJWT(cfg.JWT.Secret)
+	}
+	log.Printf("✅ JWT initialized (%s)", utils.JWTAlgorithm())
+
+	// Initialize mailer
+	mail, err := mailer.New(&cfg.Mail)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize mailer: %v", err)
+	}
+	log.Printf("✅ Mailer initialized (%s)", cfg.Mail.Driver)
+
+	// Initialize attachment storage
+	blobStore, err := storage.New(&cfg.Storage)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize attachment storage: %v", err)
+	}
+	log.Printf("✅ Attachment storage initialized (%s)", cfg.Storage.Driver)
+
+	// Initialize login brute-force protection
+	loginGuard, err := lockout.New(&cfg.Lockout, database.GetDB())
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize login tracker: %v", err)
+	}
+	loginGuard.Start(context.Background())
+	log.Printf("✅ Login tracker initialized (%s)", cfg.Lockout.Store)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(database.GetDB())
+	sessionRepo := repository.NewSessionRepository(database.GetDB())
+	userTokenRepo := repository.NewUserTokenRepository(database.GetDB())
+	apiKeyRepo := repository.NewAPIKeyRepository(database.GetDB())
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(database.GetDB())
+	oidcRepo := repository.NewOIDCRepository(database.GetDB())
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, cfg.JWT.ExpiryHours)
+	twoFactorService := services.NewTwoFactorService(userRepo, recoveryCodeRepo, cfg.Auth.TOTPIssuer)
+	authService := services.NewAuthService(userRepo, sessionRepo, userTokenRepo, twoFactorService, loginGuard, mail, services.AuthOptions{
+		AccessExpiry:        time.Duration(cfg.JWT.AccessExpiryMinutes) * time.Minute,
+		RefreshExpiry:       time.Duration(cfg.JWT.RefreshExpiryHours) * time.Hour,
+		PasswordResetExpiry: time.Duration(cfg.Auth.PasswordResetExpiryMinutes) * time.Minute,
+		VerificationExpiry:  time.Duration(cfg.Auth.EmailVerificationExpiryHours) * time.Hour,
+		UnlockExpiry:        time.Duration(cfg.Lockout.UnlockExpiryMinutes) * time.Minute,
+		BaseURL:             cfg.Server.BaseURL,
+	})
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+
+	// Single sign-on is only available when an OIDC provider is configured
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled() {
+		oidcProvider = oidc.NewProvider(&cfg.OIDC)
+		log.Printf("✅ OIDC login enabled (%s)", cfg.OIDC.IssuerURL)
+	}
+	oidcService := services.NewOIDCService(oidcProvider, oidcRepo, userRepo, sessionRepo, apiKeyRepo, authService, cfg.OIDC.AutoProvision)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+
+	// Workspace initialization
+	workspaceRepo := repository.NewWorkspaceRepository(database.GetDB())
+
+	workspaceService := services.NewWorkspaceService(workspaceRepo, userRepo, mail, services.WorkspaceOptions{
+		InvitationExpiry: time.Duration(cfg.Team.InvitationExpiryHours) * time.Hour,
+		BaseURL:          cfg.Server.BaseURL,
+	})
+
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+
+	// Notification initialization
+	notificationRepo := repository.NewNotificationRepository(database.GetDB())
+
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, workspaceRepo)
+
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	// Activity initialization
+	activityRepo := repository.NewActivityRepository(database.GetDB())
+
+	activityService := services.NewActivityService(activityRepo, workspaceRepo)
+
+	activityHandler := handlers.NewActivityHandler(activityService)
 
 	// Category initialization
 	categoryRepo := repository.NewCategoryRepository(database.GetDB())
 
-	categoryService := services.NewCategoryService(categoryRepo)
+	categoryService := services.NewCategoryService(categoryRepo, workspaceRepo, activityService)
 
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
+	// Tag initialization
+	tagRepo := repository.NewTagRepository(database.GetDB())
+
+	tagService := services.NewTagService(tagRepo, workspaceRepo)
+
+	tagHandler := handlers.NewTagHandler(tagService)
+
+	// Workflow initialization
+	workflowRepo := repository.NewWorkflowRepository(database.GetDB())
+
+	workflowService := services.NewWorkflowService(workflowRepo, workspaceRepo)
+
+	workflowHandler := handlers.NewWorkflowHandler(workflowService)
+
 	// Task initialization
 	taskRepo := repository.NewTaskRepository(database.GetDB())
 
-	taskService := services.NewTaskService(taskRepo, categoryRepo)
+	taskService := services.NewTaskService(taskRepo, categoryRepo, tagRepo, workspaceRepo, workflowRepo, notificationService, activityService, services.TaskOptions{
+		MaxDepth:             cfg.Tasks.SubtaskMaxDepth,
+		CompleteParent:       models.SubtaskBehavior(cfg.Tasks.CompleteParentBehavior),
+		DeleteParent:         models.SubtaskBehavior(cfg.Tasks.DeleteParentBehavior),
+		BlockersPreventStart: cfg.Tasks.BlockersPreventStart,
+	})
 
 	taskHandler := handlers.NewTaskHandler(taskService)
 
+	// Comment initialization
+	commentRepo := repository.NewCommentRepository(database.GetDB())
+
+	commentService := services.NewCommentService(commentRepo, taskRepo, notificationService)
+
+	commentHandler := handlers.NewCommentHandler(commentService)
+
+	// Attachment initialization
+	attachmentRepo := repository.NewAttachmentRepository(database.GetDB())
+
+	attachmentService := services.NewAttachmentService(attachmentRepo, taskRepo, workspaceRepo, activityService, blobStore, services.AttachmentOptions{
+		MaxSize:      int64(cfg.Attachments.MaxSizeMB) << 20,
+		Quota:        int64(cfg.Attachments.QuotaMB) << 20,
+		AllowedTypes: cfg.Attachments.AllowedTypes,
+	})
+
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+
+	// Checklist initialization
+	checklistRepo := repository.NewChecklistRepository(database.GetDB())
+
+	checklistService := services.NewChecklistService(checklistRepo, taskRepo, workspaceRepo)
+
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+
+	// Time tracking initialization
+	timeEntryRepo := repository.NewTimeEntryRepository(database.GetDB())
+
+	timeEntryService := services.NewTimeEntryService(timeEntryRepo, taskRepo, workspaceRepo)
+
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService)
+
+	// Data export initialization
+	dataExportRepo := repository.NewDataExportRepository(database.GetDB())
+
+	dataExportService := services.NewDataExportService(dataExportRepo, userRepo, categoryRepo, taskRepo, sessionRepo, userTokenRepo, mail, services.DataExportOptions{
+		Dir:        cfg.Export.Dir,
+		Retention:  time.Duration(cfg.Export.RetentionHours) * time.Hour,
+		LinkExpiry: time.Duration(cfg.Export.LinkExpiryMinutes) * time.Minute,
+		BaseURL:    cfg.Server.BaseURL,
+	})
+	if err := dataExportService.Start(context.Background()); err != nil {
+		log.Fatalf("❌ Failed to start data export worker: %v", err)
+	}
+
+	dataExportHandler := handlers.NewDataExportHandler(dataExportService)
+
 	// Stats initialization
 	statsRepo := repository.NewStatsRepository(database.GetDB())
 
@@ -93,12 +248,36 @@ func main() {
 
 	statsHandler := handlers.NewStatsHandler(statsService)
 
+	// Admin initialization
+	if len(cfg.Auth.AdminUsernames) > 0 {
+		promoted, err := userRepo.PromoteToAdmin(cfg.Auth.AdminUsernames)
+		if err != nil {
+			log.Fatalf("❌ Failed to promote admin users: %v", err)
+		}
+		if promoted > 0 {
+			log.Printf("✅ Promoted %d user(s) to admin", promoted)
+		}
+	}
+
+	adminService := services.NewAdminService(userRepo, sessionRepo, statsRepo, loginGuard)
+
+	adminHandler := handlers.NewAdminHandler(adminService)
+
 	// Initialize Gin router
 	router := gin.Default()
 
 	// CORS middleware
 	router.Use(corsMiddleware())
 
+	// Request IDs for tracing and the activity log
+	router.Use(middleware.RequestID())
+
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(200, utils.JWKS())
+	})
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -118,40 +297,177 @@ func main() {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/2fa", authHandler.LoginTwoFactor)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.GET("/verify", authHandler.VerifyEmail)
+			auth.POST("/unlock", authHandler.UnlockAccount)
+			auth.GET("/oidc/login", oidcHandler.Login)
+			auth.GET("/oidc/callback", oidcHandler.Callback)
+			auth.GET("/me/export/:id/download", dataExportHandler.Download)
 		}
 
 		// Protected routes (authentication required)
 		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(sessionRepo, apiKeyService))
 		{
+			// Account management needs an interactive login, API keys are rejected
+			requireSession := middleware.RequireSession()
+
 			// Auth routes
-			protected.GET("/auth/me", authHandler.GetMe)
+			protected.GET("/auth/me", middleware.RequireScope("profile"), authHandler.GetMe)
+			protected.PATCH("/auth/me", requireSession, authHandler.UpdateMe)
+			protected.DELETE("/auth/me", requireSession, authHandler.DeleteMe)
+			protected.POST("/auth/me/export", requireSession, dataExportHandler.Request)
+			protected.GET("/auth/me/export", requireSession, dataExportHandler.GetAll)
+			protected.GET("/auth/me/export/:id", requireSession, dataExportHandler.GetByID)
+			protected.POST("/auth/logout", requireSession, authHandler.Logout)
+			protected.PUT("/auth/password", requireSession, authHandler.ChangePassword)
+			protected.POST("/auth/verify/resend", requireSession, authHandler.ResendVerification)
+
+			twoFactor := protected.Group("/auth/2fa")
+			twoFactor.Use(requireSession)
+			{
+				twoFactor.POST("/setup", twoFactorHandler.Setup)
+				twoFactor.POST("/confirm", twoFactorHandler.Confirm)
+				twoFactor.POST("/disable", twoFactorHandler.Disable)
+				twoFactor.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			}
+
+			apiKeys := protected.Group("/api-keys")
+			apiKeys.Use(requireSession)
+			{
+				apiKeys.GET("", apiKeyHandler.GetAll)
+				apiKeys.POST("", apiKeyHandler.Create)
+				apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+			}
+
+			// Creating resources requires a verified email when REQUIRE_EMAIL_VERIFICATION is enabled
+			requireVerified := middleware.RequireVerifiedEmail(userRepo, cfg.Auth.RequireEmailVerification)
+
+			workspaces := protected.Group("/workspaces")
+			workspaces.Use(middleware.RequireScope("workspaces"))
+			{
+				workspaces.GET("", workspaceHandler.GetAll)
+				workspaces.POST("", requireVerified, workspaceHandler.Create)
+				workspaces.GET("/:id", workspaceHandler.GetByID)
+				workspaces.PUT("/:id", workspaceHandler.Update)
+				workspaces.DELETE("/:id", workspaceHandler.Delete)
+				workspaces.GET("/:id/members", workspaceHandler.GetMembers)
+				workspaces.PUT("/:id/members/:userId", workspaceHandler.UpdateMemberRole)
+				workspaces.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+				workspaces.GET("/:id/invitations", workspaceHandler.GetInvitations)
+				workspaces.POST("/:id/invitations", workspaceHandler.CreateInvitation)
+				workspaces.DELETE("/:id/invitations/:invitationId", workspaceHandler.RevokeInvitation)
+			}
+
+			protected.POST("/invitations/accept", requireSession, workspaceHandler.AcceptInvitation)
 
 			categories := protected.Group("/categories")
+			categories.Use(middleware.RequireScope("categories"))
 			{
 				categories.GET("", categoryHandler.GetAll)
-				categories.POST("", categoryHandler.Create)
+				categories.POST("", requireVerified, categoryHandler.Create)
 				categories.GET("/:id", categoryHandler.GetByID)
 				categories.PUT("/:id", categoryHandler.Update)
 				categories.DELETE("/:id", categoryHandler.Delete)
 			}
 
+			tags := protected.Group("/tags")
+			tags.Use(middleware.RequireScope("tags"))
+			{
+				tags.GET("", tagHandler.GetAll)
+				tags.POST("", requireVerified, tagHandler.Create)
+				tags.GET("/:id", tagHandler.GetByID)
+				tags.PUT("/:id", tagHandler.Update)
+				tags.DELETE("/:id", tagHandler.Delete)
+			}
+
+			workflows := protected.Group("/workflows")
+			workflows.Use(middleware.RequireScope("workflows"))
+			{
+				workflows.GET("", workflowHandler.GetAll)
+				workflows.POST("", requireVerified, workflowHandler.Create)
+				workflows.GET("/effective", workflowHandler.GetEffective)
+				workflows.GET("/:id", workflowHandler.GetByID)
+				workflows.PUT("/:id", workflowHandler.Update)
+				workflows.DELETE("/:id", workflowHandler.Delete)
+			}
+
 			tasks := protected.Group("/tasks")
+			tasks.Use(middleware.RequireScope("tasks"))
 			{
 				tasks.GET("", taskHandler.GetAllTasks)
-				tasks.POST("", taskHandler.CreateTask)
+				tasks.POST("", requireVerified, taskHandler.CreateTask)
 				tasks.GET("/:id", taskHandler.GetTaskByID)
+				tasks.GET("/:id/history", taskHandler.GetTaskHistory)
+				tasks.GET("/:id/subtasks", taskHandler.GetSubtasks)
 				tasks.PUT("/:id", taskHandler.UpdateTask)
 				tasks.PATCH("/:id/status", taskHandler.UpdateTaskStatus)
 				tasks.PATCH("/bulk/status", taskHandler.BulkUpdateStatus)
+				tasks.GET("/series/:seriesId", taskHandler.GetSeries)
+				tasks.PUT("/series/:seriesId", taskHandler.UpdateSeries)
+				tasks.POST("/series/:seriesId/stop", taskHandler.StopSeries)
 				tasks.DELETE("/:id", taskHandler.DeleteTask)
+				tasks.POST("/:id/assignees", taskHandler.AssignTask)
+				tasks.DELETE("/:id/assignees/:userId", taskHandler.UnassignTask)
+				tasks.POST("/:id/dependencies", taskHandler.AddDependency)
+				tasks.DELETE("/:id/dependencies/:blockerId", taskHandler.RemoveDependency)
+				tasks.GET("/:id/comments", commentHandler.GetAll)
+				tasks.POST("/:id/comments", requireVerified, commentHandler.Create)
+				tasks.GET("/:id/comments/:commentId", commentHandler.GetByID)
+				tasks.PUT("/:id/comments/:commentId", commentHandler.Update)
+				tasks.DELETE("/:id/comments/:commentId", commentHandler.Delete)
+				tasks.GET("/:id/attachments", attachmentHandler.GetAll)
+				tasks.POST("/:id/attachments", requireVerified, attachmentHandler.Upload)
+				tasks.GET("/:id/attachments/:attachmentId", attachmentHandler.Download)
+				tasks.DELETE("/:id/attachments/:attachmentId", attachmentHandler.Delete)
+				tasks.GET("/:id/checklist", checklistHandler.GetAll)
+				tasks.POST("/:id/checklist", requireVerified, checklistHandler.Create)
+				tasks.PUT("/:id/checklist/order", checklistHandler.Reorder)
+				tasks.PUT("/:id/checklist/:itemId", checklistHandler.Update)
+				tasks.DELETE("/:id/checklist/:itemId", checklistHandler.Delete)
+				tasks.GET("/timer", timeEntryHandler.GetRunning)
+				tasks.POST("/:id/timer/start", requireVerified, timeEntryHandler.StartTimer)
+				tasks.POST("/:id/timer/stop", timeEntryHandler.StopTimer)
+				tasks.GET("/:id/time-entries", timeEntryHandler.GetAll)
+				tasks.POST("/:id/time-entries", requireVerified, timeEntryHandler.Create)
+				tasks.PUT("/:id/time-entries/:entryId", timeEntryHandler.Update)
+				tasks.DELETE("/:id/time-entries/:entryId", timeEntryHandler.Delete)
 			}
 
 			stats := protected.Group("/stats")
+			stats.Use(middleware.RequireScope("stats"))
 			{
 				stats.GET("/dashboard", statsHandler.GetDashboardStats)
 				stats.GET("/upcoming", statsHandler.GetUpcomingTasks)
 				stats.GET("/overdue", statsHandler.GetOverdueTasks)
+				stats.GET("/time", statsHandler.GetTimeReport)
+			}
+
+			protected.GET("/activity", middleware.RequireScope("activity"), activityHandler.GetFeed)
+
+			notifications := protected.Group("/notifications")
+			notifications.Use(middleware.RequireScope("notifications"))
+			{
+				notifications.GET("", notificationHandler.GetAll)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.PATCH("/read-all", notificationHandler.MarkAllRead)
+				notifications.PATCH("/:id/read", notificationHandler.MarkRead)
+			}
+
+			admin := protected.Group("/admin")
+			admin.Use(requireSession, middleware.RequireRole(models.UserRoleAdmin))
+			{
+				admin.GET("/users", adminHandler.ListUsers)
+				admin.GET("/users/:id", adminHandler.GetUser)
+				admin.POST("/users/:id/disable", adminHandler.DisableUser)
+				admin.POST("/users/:id/enable", adminHandler.EnableUser)
+				admin.PUT("/users/:id/role", adminHandler.UpdateUserRole)
+				admin.POST("/users/:id/logout", adminHandler.ForceLogout)
+				admin.POST("/users/:id/unlock", adminHandler.UnlockUser)
+				admin.GET("/stats", adminHandler.GetSystemStats)
 			}
 		}
 
@@ -163,9 +479,49 @@ func main() {
 				"status":  "active",
 				"endpoints": gin.H{
 					"auth": gin.H{
-						"register": "POST /api/v1/auth/register",
-						"login":    "POST /api/v1/auth/login",
-						"me":       "GET /api/v1/auth/me (protected)",
+						"register":           "POST /api/v1/auth/register",
+						"login":              "POST /api/v1/auth/login",
+						"login_2fa":          "POST /api/v1/auth/login/2fa",
+						"2fa_setup":          "POST /api/v1/auth/2fa/setup (protected)",
+						"2fa_confirm":        "POST /api/v1/auth/2fa/confirm (protected)",
+						"2fa_disable":        "POST /api/v1/auth/2fa/disable (protected)",
+						"2fa_recovery_codes": "POST /api/v1/auth/2fa/recovery-codes (protected)",
+						"refresh":            "POST /api/v1/auth/refresh",
+						"logout":             "POST /api/v1/auth/logout (protected)",
+						"me":                 "GET /api/v1/auth/me (protected)",
+						"update_me":          "PATCH /api/v1/auth/me (protected)",
+						"delete_me":          "DELETE /api/v1/auth/me (protected)",
+						"request_export":     "POST /api/v1/auth/me/export (protected)",
+						"list_exports":       "GET /api/v1/auth/me/export (protected)",
+						"get_export":         "GET /api/v1/auth/me/export/:id (protected)",
+						"download_export":    "GET /api/v1/auth/me/export/:id/download?token=",
+						"forgot_password":    "POST /api/v1/auth/password/forgot",
+						"reset_password":     "POST /api/v1/auth/password/reset",
+						"change_password":    "PUT /api/v1/auth/password (protected)",
+						"verify_email":       "GET /api/v1/auth/verify?token=",
+						"resend_verify":      "POST /api/v1/auth/verify/resend (protected)",
+						"unlock_account":     "POST /api/v1/auth/unlock",
+						"oidc_login":         "GET /api/v1/auth/oidc/login",
+						"oidc_callback":      "GET /api/v1/auth/oidc/callback",
+					},
+					"api_keys": gin.H{
+						"list":   "GET /api/v1/api-keys (protected)",
+						"create": "POST /api/v1/api-keys (protected)",
+						"revoke": "DELETE /api/v1/api-keys/:id (protected)",
+					},
+					"workspaces": gin.H{
+						"list":              "GET /api/v1/workspaces (protected)",
+						"create":            "POST /api/v1/workspaces (protected)",
+						"get":               "GET /api/v1/workspaces/:id (protected)",
+						"update":            "PUT /api/v1/workspaces/:id (protected)",
+						"delete":            "DELETE /api/v1/workspaces/:id (protected)",
+						"members":           "GET /api/v1/workspaces/:id/members (protected)",
+						"update_member":     "PUT /api/v1/workspaces/:id/members/:userId (protected)",
+						"remove_member":     "DELETE /api/v1/workspaces/:id/members/:userId (protected)",
+						"invitations":       "GET /api/v1/workspaces/:id/invitations (protected)",
+						"create_invitation": "POST /api/v1/workspaces/:id/invitations (protected)",
+						"revoke_invitation": "DELETE /api/v1/workspaces/:id/invitations/:invitationId (protected)",
+						"accept_invitation": "POST /api/v1/invitations/accept (protected)",
 					},
 					"categories": gin.H{
 						"list":   "GET /api/v1/categories (protected)",
@@ -174,18 +530,89 @@ func main() {
 						"update": "PUT /api/v1/categories/:id (protected)",
 						"delete": "DELETE /api/v1/categories/:id (protected)",
 					},
+					"tags": gin.H{
+						"list":   "GET /api/v1/tags (protected)",
+						"create": "POST /api/v1/tags (protected)",
+						"get":    "GET /api/v1/tags/:id (protected)",
+						"update": "PUT /api/v1/tags/:id (protected)",
+						"delete": "DELETE /api/v1/tags/:id (protected)",
+					},
+					"workflows": gin.H{
+						"list":      "GET /api/v1/workflows (protected)",
+						"create":    "POST /api/v1/workflows (protected)",
+						"effective": "GET /api/v1/workflows/effective (protected)",
+						"get":       "GET /api/v1/workflows/:id (protected)",
+						"update":    "PUT /api/v1/workflows/:id (protected)",
+						"delete":    "DELETE /api/v1/workflows/:id (protected)",
+					},
 					"tasks": gin.H{
-						"list":          "GET /api/v1/tasks (protected)",
-						"create":        "POST /api/v1/tasks (protected)",
-						"get":           "GET /api/v1/tasks/:id (protected)",
-						"update":        "PUT /api/v1/tasks/:id (protected)",
-						"update_status": "PATCH /api/v1/tasks/:id/status (protected)",
-						"delete":        "DELETE /api/v1/tasks/:id (protected)",
+						"list":           "GET /api/v1/tasks (protected)",
+						"create":         "POST /api/v1/tasks (protected)",
+						"get":            "GET /api/v1/tasks/:id (protected)",
+						"history":        "GET /api/v1/tasks/:id/history (protected)",
+						"subtasks":       "GET /api/v1/tasks/:id/subtasks (protected)",
+						"update":         "PUT /api/v1/tasks/:id (protected)",
+						"update_status":  "PATCH /api/v1/tasks/:id/status (protected)",
+						"delete":         "DELETE /api/v1/tasks/:id (protected)",
+						"assign":         "POST /api/v1/tasks/:id/assignees (protected)",
+						"unassign":       "DELETE /api/v1/tasks/:id/assignees/:userId (protected)",
+						"add_blocker":    "POST /api/v1/tasks/:id/dependencies (protected)",
+						"remove_blocker": "DELETE /api/v1/tasks/:id/dependencies/:blockerId (protected)",
+						"get_series":     "GET /api/v1/tasks/series/:seriesId (protected)",
+						"update_series":  "PUT /api/v1/tasks/series/:seriesId (protected)",
+						"stop_series":    "POST /api/v1/tasks/series/:seriesId/stop (protected)",
+					},
+					"comments": gin.H{
+						"list":   "GET /api/v1/tasks/:id/comments (protected)",
+						"create": "POST /api/v1/tasks/:id/comments (protected)",
+						"get":    "GET /api/v1/tasks/:id/comments/:commentId (protected)",
+						"update": "PUT /api/v1/tasks/:id/comments/:commentId (protected)",
+						"delete": "DELETE /api/v1/tasks/:id/comments/:commentId (protected)",
+					},
+					"attachments": gin.H{
+						"list":     "GET /api/v1/tasks/:id/attachments (protected)",
+						"upload":   "POST /api/v1/tasks/:id/attachments (protected)",
+						"download": "GET /api/v1/tasks/:id/attachments/:attachmentId (protected)",
+						"delete":   "DELETE /api/v1/tasks/:id/attachments/:attachmentId (protected)",
+					},
+					"checklist": gin.H{
+						"list":    "GET /api/v1/tasks/:id/checklist (protected)",
+						"create":  "POST /api/v1/tasks/:id/checklist (protected)",
+						"update":  "PUT /api/v1/tasks/:id/checklist/:itemId (protected)",
+						"delete":  "DELETE /api/v1/tasks/:id/checklist/:itemId (protected)",
+						"reorder": "PUT /api/v1/tasks/:id/checklist/order (protected)",
+					},
+					"time_tracking": gin.H{
+						"running_timer": "GET /api/v1/tasks/timer (protected)",
+						"start_timer":   "POST /api/v1/tasks/:id/timer/start (protected)",
+						"stop_timer":    "POST /api/v1/tasks/:id/timer/stop (protected)",
+						"list":          "GET /api/v1/tasks/:id/time-entries (protected)",
+						"create":        "POST /api/v1/tasks/:id/time-entries (protected)",
+						"update":        "PUT /api/v1/tasks/:id/time-entries/:entryId (protected)",
+						"delete":        "DELETE /api/v1/tasks/:id/time-entries/:entryId (protected)",
 					},
 					"stats": gin.H{
 						"dashboard": "GET /api/v1/stats/dashboard (protected)",
 						"upcoming":  "GET /api/v1/stats/upcoming (protected)",
 						"overdue":   "GET /api/v1/stats/overdue (protected)",
+						"time":      "GET /api/v1/stats/time (protected)",
+					},
+					"activity": "GET /api/v1/activity (protected)",
+					"notifications": gin.H{
+						"list":          "GET /api/v1/notifications (protected)",
+						"unread_count":  "GET /api/v1/notifications/unread-count (protected)",
+						"mark_read":     "PATCH /api/v1/notifications/:id/read (protected)",
+						"mark_all_read": "PATCH /api/v1/notifications/read-all (protected)",
+					},
+					"admin": gin.H{
+						"list_users":   "GET /api/v1/admin/users (admin)",
+						"get_user":     "GET /api/v1/admin/users/:id (admin)",
+						"disable_user": "POST /api/v1/admin/users/:id/disable (admin)",
+						"enable_user":  "POST /api/v1/admin/users/:id/enable (admin)",
+						"update_role":  "PUT /api/v1/admin/users/:id/role (admin)",
+						"force_logout": "POST /api/v1/admin/users/:id/logout (admin)",
+						"unlock_user":  "POST /api/v1/admin/users/:id/unlock (admin)",
+						"stats":        "GET /api/v1/admin/stats (admin)",
 					},
 				},
 			})
@@ -215,22 +642,124 @@ func main() {
 	log.Println("   --- Authentication ---")
 	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/register")
 	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/login")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/login/2fa")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/refresh")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/logout (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/password/forgot")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/password/reset")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/auth/password (protected)")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/verify?token=")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/verify/resend (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/unlock")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/oidc/login")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/oidc/callback")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/2fa/setup (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/2fa/confirm (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/2fa/disable (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/2fa/recovery-codes (protected)")
 	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/me (protected)")
+	log.Println("   PATCH  http://localhost" + serverAddr + "/api/v1/auth/me (protected)")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/auth/me (protected)")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/auth/me/export (protected)")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/me/export (protected)")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/me/export/:id (protected)")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/auth/me/export/:id/download?token=")
+	log.Println("   --- API Keys (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/api-keys")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/api-keys")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/api-keys/:id")
+	log.Println("   --- Workspaces (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workspaces")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/workspaces")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workspaces/:id")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/workspaces/:id")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/workspaces/:id")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workspaces/:id/members")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/workspaces/:id/members/:userId")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/workspaces/:id/members/:userId")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workspaces/:id/invitations")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/workspaces/:id/invitations")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/workspaces/:id/invitations/:invitationId")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/invitations/accept")
 	log.Println("   --- Categories (protected) ---")
 	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/categories")
 	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/categories")
 	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/categories/:id")
 	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/categories/:id")
 	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/categories/:id")
+	log.Println("   --- Tags (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tags")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tags")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tags/:id")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tags/:id")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tags/:id")
+	log.Println("   --- Workflows (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workflows")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/workflows")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workflows/effective")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/workflows/:id")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/workflows/:id")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/workflows/:id")
 	log.Println("   --- Tasks (protected) ---")
 	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks")
 	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks")
 	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/history")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/subtasks")
 	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/:id")
 	log.Println("   PATCH  http://localhost" + serverAddr + "/api/v1/tasks/:id/status")
 	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/assignees")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/assignees/:userId")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/dependencies")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/dependencies/:blockerId")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/series/:seriesId")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/series/:seriesId")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/series/:seriesId/stop")
+	log.Println("   --- Comments (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/comments")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/comments")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/comments/:commentId")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/:id/comments/:commentId")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/comments/:commentId")
+	log.Println("   --- Attachments (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/attachments")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/attachments")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/attachments/:attachmentId")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/attachments/:attachmentId")
+	log.Println("   --- Checklist (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/checklist")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/checklist")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/:id/checklist/order")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/:id/checklist/:itemId")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/checklist/:itemId")
+	log.Println("   --- Time Tracking (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/timer")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/timer/start")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/timer/stop")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/tasks/:id/time-entries")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/tasks/:id/time-entries")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/tasks/:id/time-entries/:entryId")
+	log.Println("   DELETE http://localhost" + serverAddr + "/api/v1/tasks/:id/time-entries/:entryId")
+	log.Println("   --- Activity (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/activity")
+	log.Println("   --- Notifications (protected) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/notifications")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/notifications/unread-count")
+	log.Println("   PATCH  http://localhost" + serverAddr + "/api/v1/notifications/:id/read")
+	log.Println("   PATCH  http://localhost" + serverAddr + "/api/v1/notifications/read-all")
+	log.Println("   --- Admin (admin role) ---")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/admin/users")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/admin/users/:id")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/admin/users/:id/disable")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/admin/users/:id/enable")
+	log.Println("   PUT    http://localhost" + serverAddr + "/api/v1/admin/users/:id/role")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/admin/users/:id/logout")
+	log.Println("   POST   http://localhost" + serverAddr + "/api/v1/admin/users/:id/unlock")
+	log.Println("   GET    http://localhost" + serverAddr + "/api/v1/admin/stats")
 	log.Println("   --- Health ---")
 	log.Println("   GET    http://localhost" + serverAddr + "/health")
+	log.Println("   GET    http://localhost" + serverAddr + "/.well-known/jwks.json")
 	log.Printf("📚 Swagger: http://localhost%s/swagger/index.html (Phase 5)", serverAddr)
 
 	if err := router.Run(serverAddr); err != nil {
@@ -243,8 +772,9 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Retry-After, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -5,19 +5,30 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Mail        MailConfig
+	Lockout     LockoutConfig
+	OIDC        OIDCConfig
+	Export      ExportConfig
+	Team        TeamConfig
+	Tasks       TaskConfig
+	Attachments AttachmentConfig
+	Storage     StorageConfig
 }
 
 type ServerConfig struct {
 	Port    string
 	GinMode string
+	BaseURL string
 }
 
 type DatabaseConfig struct {
@@ -29,8 +40,92 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Secret              string
+	PrivateKeyFile      string   // PEM RSA or Ed25519 key; enables RS256/EdDSA instead of HS256
+	PublicKeyFiles      []string // additional verification keys, e.g. the previous key after a rotation
+	AccessExpiryMinutes int
+	RefreshExpiryHours  int
+}
+
+type AuthConfig struct {
+	PasswordResetExpiryMinutes   int
+	EmailVerificationExpiryHours int
+	RequireEmailVerification     bool
+	AdminUsernames               []string
+	TOTPIssuer                   string
+}
+
+type MailConfig struct {
+	Driver       string // "smtp" or "outbox"
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutboxDir    string
+}
+
+type LockoutConfig struct {
+	Store               string // "memory" or "postgres"
+	FreeAttempts        int
+	MaxAttempts         int
+	IPFreeAttempts      int
+	BackoffBaseSeconds  int
+	BackoffMaxSeconds   int
+	LockoutMinutes      int
+	WindowMinutes       int
+	UnlockExpiryMinutes int
+}
+
+type OIDCConfig struct {
+	IssuerURL     string // login with OpenID Connect is enabled when set
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	AuthURL       string // optional, discovered from the issuer when empty
+	TokenURL      string // optional, discovered from the issuer when empty
+	JWKSURL       string // optional, discovered from the issuer when empty
+	AutoProvision bool   // create accounts for unknown users
+}
+
+type ExportConfig struct {
+	Dir               string // where generated archives are stored
+	RetentionHours    int    // archives are deleted after this long
+	LinkExpiryMinutes int    // lifetime of a download link
+}
+
+type TeamConfig struct {
+	InvitationExpiryHours int
+}
+
+type TaskConfig struct {
+	SubtaskMaxDepth        int    // levels of nesting including the top-level task
+	CompleteParentBehavior string // "block", "cascade" or "detach"
+	DeleteParentBehavior   string // "block", "cascade" or "detach"
+	BlockersPreventStart   bool   // open blockers also prevent moving a task to in_progress
+}
+
+type AttachmentConfig struct {
+	MaxSizeMB    int      // largest accepted upload
+	QuotaMB      int      // total size of the attachments a user may upload
+	AllowedTypes []string // accepted MIME types
+}
+
+type StorageConfig struct {
+	Driver      string // "local" or "s3"
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool // address the bucket in the path, as MinIO expects
+}
+
+// Enabled reports whether OpenID Connect login is configured
+func (c *OIDCConfig) Enabled() bool {
+	return c.IssuerURL != "" && c.ClientID != ""
 }
 
 // LoadConfig loads configuration from environment variables
@@ -40,15 +135,53 @@ func LoadConfig() (*Config, error) {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	expiryHours, err := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	accessExpiryMinutes, err := strconv.Atoi(getEnv("JWT_ACCESS_EXPIRY_MINUTES", "15"))
+	if err != nil {
+		accessExpiryMinutes = 15
+	}
+
+	refreshExpiryHours, err := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRY_HOURS", "168"))
+	if err != nil {
+		refreshExpiryHours = 168
+	}
+
+	passwordResetExpiryMinutes, err := strconv.Atoi(getEnv("PASSWORD_RESET_EXPIRY_MINUTES", "60"))
+	if err != nil {
+		passwordResetExpiryMinutes = 60
+	}
+
+	emailVerificationExpiryHours, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_EXPIRY_HOURS", "48"))
+	if err != nil {
+		emailVerificationExpiryHours = 48
+	}
+
+	requireEmailVerification, err := strconv.ParseBool(getEnv("REQUIRE_EMAIL_VERIFICATION", "false"))
+	if err != nil {
+		requireEmailVerification = false
+	}
+
+	oidcAutoProvision, err := strconv.ParseBool(getEnv("OIDC_AUTO_PROVISION", "true"))
+	if err != nil {
+		oidcAutoProvision = true
+	}
+
+	blockersPreventStart, err := strconv.ParseBool(getEnv("DEPENDENCY_BLOCKS_IN_PROGRESS", "false"))
 	if err != nil {
-		expiryHours = 24
+		blockersPreventStart = false
 	}
 
+	s3PathStyle, err := strconv.ParseBool(getEnv("S3_PATH_STYLE", "true"))
+	if err != nil {
+		s3PathStyle = true
+	}
+
+	baseURL := getEnv("APP_BASE_URL", "http://localhost:8080")
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:    getEnv("SERVER_PORT", "8080"),
 			GinMode: getEnv("GIN_MODE", "debug"),
+			BaseURL: baseURL,
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -58,8 +191,78 @@ func LoadConfig() (*Config, error) {
 			DBName:   getEnv("DB_NAME", "taskmanagement"),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "default-secret-change-this"),
-			ExpiryHours: expiryHours,
+			Secret:              getEnv("JWT_SECRET", "default-secret-change-this"),
+			PrivateKeyFile:      getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			PublicKeyFiles:      splitList(getEnv("JWT_PUBLIC_KEY_FILES", "")),
+			AccessExpiryMinutes: accessExpiryMinutes,
+			RefreshExpiryHours:  refreshExpiryHours,
+		},
+		Auth: AuthConfig{
+			PasswordResetExpiryMinutes:   passwordResetExpiryMinutes,
+			EmailVerificationExpiryHours: emailVerificationExpiryHours,
+			RequireEmailVerification:     requireEmailVerification,
+			AdminUsernames:               splitList(getEnv("ADMIN_USERNAMES", "")),
+			TOTPIssuer:                   getEnv("TOTP_ISSUER", "Task Management API"),
+		},
+		Lockout: LockoutConfig{
+			Store:               getEnv("LOGIN_TRACKER_STORE", "memory"),
+			FreeAttempts:        getEnvInt("LOGIN_FREE_ATTEMPTS", 3),
+			MaxAttempts:         getEnvInt("LOGIN_MAX_ATTEMPTS", 10),
+			IPFreeAttempts:      getEnvInt("LOGIN_IP_FREE_ATTEMPTS", 20),
+			BackoffBaseSeconds:  getEnvInt("LOGIN_BACKOFF_BASE_SECONDS", 1),
+			BackoffMaxSeconds:   getEnvInt("LOGIN_BACKOFF_MAX_SECONDS", 300),
+			LockoutMinutes:      getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
+			WindowMinutes:       getEnvInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 15),
+			UnlockExpiryMinutes: getEnvInt("ACCOUNT_UNLOCK_EXPIRY_MINUTES", 60),
+		},
+		OIDC: OIDCConfig{
+			IssuerURL:     getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:      getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:   getEnv("OIDC_REDIRECT_URL", strings.TrimSuffix(baseURL, "/")+"/api/v1/auth/oidc/callback"),
+			Scopes:        strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+			AuthURL:       getEnv("OIDC_AUTH_URL", ""),
+			TokenURL:      getEnv("OIDC_TOKEN_URL", ""),
+			JWKSURL:       getEnv("OIDC_JWKS_URL", ""),
+			AutoProvision: oidcAutoProvision,
+		},
+		Export: ExportConfig{
+			Dir:               getEnv("EXPORT_DIR", "tmp/exports"),
+			RetentionHours:    getEnvInt("EXPORT_RETENTION_HOURS", 24),
+			LinkExpiryMinutes: getEnvInt("EXPORT_LINK_EXPIRY_MINUTES", 60),
+		},
+		Team: TeamConfig{
+			InvitationExpiryHours: getEnvInt("WORKSPACE_INVITATION_EXPIRY_HOURS", 168),
+		},
+		Tasks: TaskConfig{
+			SubtaskMaxDepth:        getEnvInt("SUBTASK_MAX_DEPTH", 3),
+			CompleteParentBehavior: getEnv("SUBTASK_COMPLETE_BEHAVIOR", "block"),
+			DeleteParentBehavior:   getEnv("SUBTASK_DELETE_BEHAVIOR", "block"),
+			BlockersPreventStart:   blockersPreventStart,
+		},
+		Attachments: AttachmentConfig{
+			MaxSizeMB:    getEnvInt("ATTACHMENT_MAX_SIZE_MB", 10),
+			QuotaMB:      getEnvInt("ATTACHMENT_QUOTA_MB", 500),
+			AllowedTypes: splitList(getEnv("ATTACHMENT_ALLOWED_TYPES", "image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain,text/markdown,text/csv,application/zip")),
+		},
+		Storage: StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:    getEnv("STORAGE_LOCAL_DIR", "tmp/attachments"),
+			S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle: s3PathStyle,
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "outbox"),
+			From:         getEnv("MAIL_FROM", "Task Management API <no-reply@taskmanagement.local>"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			OutboxDir:    getEnv("MAIL_OUTBOX_DIR", ""),
 		},
 	}
 
@@ -68,7 +271,21 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("DB_PASSWORD is required")
 	}
 
-	if len(config.JWT.Secret) < 32 {
+	if config.Tasks.SubtaskMaxDepth < 1 {
+		return nil, fmt.Errorf("SUBTASK_MAX_DEPTH must be at least 1")
+	}
+	if !validSubtaskBehavior(config.Tasks.CompleteParentBehavior) {
+		return nil, fmt.Errorf("SUBTASK_COMPLETE_BEHAVIOR must be block, cascade or detach")
+	}
+	if !validSubtaskBehavior(config.Tasks.DeleteParentBehavior) {
+		return nil, fmt.Errorf("SUBTASK_DELETE_BEHAVIOR must be block, cascade or detach")
+	}
+
+	if config.Attachments.MaxSizeMB < 1 || config.Attachments.QuotaMB < config.Attachments.MaxSizeMB {
+		return nil, fmt.Errorf("ATTACHMENT_MAX_SIZE_MB must be at least 1 and at most ATTACHMENT_QUOTA_MB")
+	}
+
+	if config.JWT.PrivateKeyFile == "" && len(config.JWT.Secret) < 32 {
 		log.Println("WARNING: JWT_SECRET should be at least 32 characters long")
 	}
 
@@ -90,3 +307,28 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt gets an integer environment variable with fallback
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// validSubtaskBehavior checks what happens to subtasks when their parent is completed or deleted
+func validSubtaskBehavior(value string) bool {
+	return value == "block" || value == "cascade" || value == "detach"
+}
+
+// splitList splits a comma separated value into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -55,13 +55,45 @@ func AutoMigrate() error {
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Category{},
+		&models.Tag{},
+		&models.Workflow{},
+		&models.WorkflowStatus{},
+		&models.WorkflowTransition{},
+		&models.Attachment{},
+		&models.ChecklistItem{},
+		&models.TimeEntry{},
 		&models.Task{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.UserToken{},
+		&models.APIKey{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.UserIdentity{},
+		&models.OIDCLoginState{},
+		&models.DataExport{},
+		&models.Workspace{},
+		&models.WorkspaceMember{},
+		&models.WorkspaceInvitation{},
+		&models.TaskAssignee{},
+		&models.TaskDependency{},
+		&models.TaskSeries{},
+		&models.Comment{},
+		&models.CommentEdit{},
+		&models.Notification{},
+		&models.Activity{},
+		&models.ActivityChange{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Tasks created before workflows existed are in the default status of the same name
+	if err := DB.Exec("UPDATE tasks SET workflow_status = status WHERE workflow_status IS NULL OR workflow_status = ''").Error; err != nil {
+		return fmt.Errorf("failed to backfill workflow statuses: %w", err)
+	}
+
 	log.Println("✅ Database migrations completed successfully!")
 	return nil
 }
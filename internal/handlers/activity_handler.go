@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type ActivityHandler struct {
+	activityService *services.ActivityService
+}
+
+// NewActivityHandler creates a new activity handler
+func NewActivityHandler(activityService *services.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetFeed godoc
+// @Summary Activity feed
+// @Description Get the changes to the user's personal tasks and categories and to those of the user's workspaces, newest first
+// @Tags activity
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string false "Filter by entity type (task, category)"
+// @Param workspace_id query int false "Filter by workspace ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Activity retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /activity [get]
+func (h *ActivityHandler) GetFeed(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var filter models.ActivityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	activities, total, err := h.activityService.GetFeed(userID.(uint), filter)
+	if err != nil {
+		if errors.Is(err, utils.ErrWorkspaceNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve activity")
+		return
+	}
+
+	filter.SetDefaults()
+	utils.PaginatedResponse(c, http.StatusOK, "Activity retrieved successfully", activities, total, filter.Page, filter.PageSize)
+}
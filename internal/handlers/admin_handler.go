@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List and search all users (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Search in username, email and full name"
+// @Param role query string false "Filter by role (user, admin)"
+// @Param status query string false "Filter by status (active, disabled)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var filter models.AdminUserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	users, total, err := h.adminService.ListUsers(filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve users")
+		return
+	}
+
+	filter.SetDefaults()
+	utils.PaginatedResponse(c, http.StatusOK, "Users retrieved successfully", users, total, filter.Page, filter.PageSize)
+}
+
+// GetUser godoc
+// @Summary Get user
+// @Description Get a user by ID (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.User
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.GetUser(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", user)
+}
+
+// DisableUser godoc
+// @Summary Disable user
+// @Description Disable an account and revoke all of its sessions (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/disable [post]
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.DisableUser(adminID.(uint), uint(id))
+	if err != nil {
+		h.handleUserError(c, err, "Failed to disable user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User disabled successfully", user)
+}
+
+// EnableUser godoc
+// @Summary Enable user
+// @Description Re-enable a disabled account (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.User
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/enable [post]
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.EnableUser(uint(id))
+	if err != nil {
+		h.handleUserError(c, err, "Failed to enable user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User enabled successfully", user)
+}
+
+// UpdateUserRole godoc
+// @Summary Change user role
+// @Description Change the role of a user and revoke their sessions (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.UpdateUserRoleRequest true "New role"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.adminService.UpdateUserRole(adminID.(uint), uint(id), req.Role)
+	if err != nil {
+		h.handleUserError(c, err, "Failed to update user role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", user)
+}
+
+// ForceLogout godoc
+// @Summary Force logout user
+// @Description Revoke every active session of a user (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	revoked, err := h.adminService.ForceLogout(uint(id))
+	if err != nil {
+		h.handleUserError(c, err, "Failed to logout user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User logged out from all sessions", gin.H{
+		"revoked_sessions": revoked,
+	})
+}
+
+// UnlockUser godoc
+// @Summary Unlock user
+// @Description Lift a login lockout and clear failed login attempts of a user (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	status, err := h.adminService.UnlockUser(uint(id))
+	if err != nil {
+		h.handleUserError(c, err, "Failed to unlock user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unlocked successfully", gin.H{
+		"was_locked":      status.Locked,
+		"failed_attempts": status.Failures,
+	})
+}
+
+// GetSystemStats godoc
+// @Summary Get system statistics
+// @Description Get system-wide user, task, category and session counts (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SystemStats
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/stats [get]
+func (h *AdminHandler) GetSystemStats(c *gin.Context) {
+	stats, err := h.adminService.GetSystemStats()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve statistics")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statistics retrieved successfully", stats)
+}
+
+// handleUserError maps admin service errors to HTTP responses
+func (h *AdminHandler) handleUserError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrCannotModifySelf):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// multipartOverhead allows for the multipart framing around the uploaded file
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// Upload godoc
+// @Summary Upload an attachment
+// @Description Attach a file to a task (multipart/form-data, field "file"). Size, type and the uploader's quota are limited.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} models.Attachment
+// @Failure 400 {object} map[string]interface{} "Missing or empty file"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 413 {object} map[string]interface{} "File too large or quota exceeded"
+// @Failure 415 {object} map[string]interface{} "File type not allowed"
+// @Router /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Stop reading oversized requests early instead of buffering them
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.handleError(c, utils.ErrAttachmentTooLarge, "")
+			return
+		}
+		h.handleError(c, utils.ErrAttachmentRequired, "")
+		return
+	}
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), uint(taskID), userID.(uint), header, c.GetString("requestID"))
+	if err != nil {
+		h.handleError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// GetAll godoc
+// @Summary List attachments
+// @Description Get the attachments of a task, oldest first
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {array} models.Attachment
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Router /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAllByTask(uint(taskID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve attachments")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// Download godoc
+// @Summary Download an attachment
+// @Description Download the content of an attachment. The ETag is its SHA-256 checksum.
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task or attachment not found"
+// @Router /tasks/{id}/attachments/{attachmentId} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, attachmentID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachmentService.Open(c.Request.Context(), attachmentID, taskID, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to download attachment")
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"ETag":                   `"` + attachment.Checksum + `"`,
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-cache",
+	})
+}
+
+// Delete godoc
+// @Summary Delete an attachment
+// @Description Remove an attachment from a task and delete its content
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {object} map[string]interface{} "Attachment deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task or attachment not found"
+// @Router /tasks/{id}/attachments/{attachmentId} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, attachmentID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.Delete(attachmentID, taskID, userID.(uint), c.GetString("requestID")); err != nil {
+		h.handleError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachment deleted successfully", nil)
+}
+
+// parseIDs reads the task and attachment IDs from the URL, responding with 400 when invalid
+func (h *AttachmentHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID")
+		return 0, 0, false
+	}
+	return uint(taskID), uint(attachmentID), true
+}
+
+// handleError maps attachment service errors to HTTP responses
+func (h *AttachmentHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTaskNotFound), errors.Is(err, utils.ErrAttachmentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrAttachmentRequired), errors.Is(err, utils.ErrAttachmentEmpty):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrAttachmentTooLarge), errors.Is(err, utils.ErrAttachmentQuotaExceeded):
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, utils.ErrAttachmentTypeNotAllowed):
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
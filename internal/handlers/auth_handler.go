@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/lockout"
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"github.com/hoanghnt/TaskManagementAPI/internal/services"
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
@@ -41,7 +44,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// Call service
-	authResponse, err := h.authService.Register(&req)
+	authResponse, err := h.authService.Register(&req, clientInfo(c))
 	if err != nil {
 		if err.Error() == "username already exists" || err.Error() == "email already exists" {
 			utils.ErrorResponse(c, http.StatusConflict, err.Error())
@@ -56,14 +59,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login handles user login
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. If two-factor authentication is enabled, a challenge token is returned instead that must be completed at /auth/login/2fa.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body models.LoginRequest true "Login credentials"
 // @Success 200 {object} models.AuthResponse
+// @Success 202 {object} models.TwoFactorChallenge
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
@@ -75,15 +81,324 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Call service
-	authResponse, err := h.authService.Login(&req)
+	authResponse, challenge, err := h.authService.Login(&req, clientInfo(c))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		if lockoutResponse(c, err) {
+			return
+		}
+		if errors.Is(err, utils.ErrAccountDisabled) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidCredentials) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to login")
+		return
+	}
+
+	if challenge != nil {
+		utils.SuccessResponse(c, http.StatusAccepted, "Two-factor authentication required", challenge)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", authResponse)
+}
+
+// LoginTwoFactor handles the second login step
+// @Summary Complete two-factor login
+// @Description Exchange a login challenge token and a TOTP or recovery code for tokens
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authResponse, err := h.authService.CompleteTwoFactorLogin(&req, clientInfo(c))
+	if err != nil {
+		if lockoutResponse(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, utils.ErrInvalidChallenge), errors.Is(err, utils.ErrInvalidTwoFactorCode):
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, utils.ErrAccountDisabled):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to complete login")
+		}
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", authResponse)
 }
 
+// Refresh handles access token renewal
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a rotated refresh token. Refresh tokens are single use; reusing one revokes the session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Call service
+	authResponse, err := h.authService.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidRefreshToken) ||
+			errors.Is(err, utils.ErrRefreshTokenReused) ||
+			errors.Is(err, utils.ErrSessionRevoked) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrAccountDisabled) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", authResponse)
+}
+
+// Logout handles user logout
+// @Summary Logout user
+// @Description Revoke the current session so its access and refresh tokens can no longer be used
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	// Get session ID from context (set by auth middleware)
+	sessionID, exists := c.Get("sessionID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.Logout(sessionID.(string)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
+}
+
+// VerifyEmail handles email verification links
+// @Summary Verify email address
+// @Description Confirm the email address using the token from the verification email
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/verify [get]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(token)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidVerificationToken) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", user)
+}
+
+// ResendVerification handles resending the verification email
+// @Summary Resend verification email
+// @Description Send a new verification link to the authenticated user. Previous links stop working.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.ResendVerificationEmail(userID.(uint)); err != nil {
+		if errors.Is(err, utils.ErrEmailAlreadyVerified) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification email sent", nil)
+}
+
+// UnlockAccount handles account unlock links
+// @Summary Unlock account
+// @Description Lift a login lockout using the single-use token emailed when the account was locked
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.UnlockAccountRequest true "Unlock token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/unlock [post]
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	var req models.UnlockAccountRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.UnlockAccount(&req); err != nil {
+		if errors.Is(err, utils.ErrInvalidUnlockToken) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to unlock account")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account unlocked successfully", nil)
+}
+
+// ForgotPassword handles password reset requests
+// @Summary Request password reset
+// @Description Email a single-use password reset link. Always succeeds so registered emails cannot be discovered.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ForgotPassword(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process password reset request")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If an account with that email exists, a password reset link has been sent", nil)
+}
+
+// ResetPassword handles resetting a password with a reset token
+// @Summary Reset password
+// @Description Set a new password using a reset token. All sessions of the user are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(&req); err != nil {
+		if errors.Is(err, utils.ErrInvalidResetToken) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// ChangePassword handles changing the password of the current user
+// @Summary Change password
+// @Description Change the password of the authenticated user. Other sessions are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	// Get user info from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	sessionID := c.GetString("sessionID")
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ChangePassword(userID.(uint), sessionID, &req); err != nil {
+		if errors.Is(err, utils.ErrIncorrectPassword) || errors.Is(err, utils.ErrPasswordUnchanged) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password changed successfully", nil)
+}
+
 // GetMe handles getting current user info
 // @Summary Get current user
 // @Description Get authenticated user information
@@ -110,3 +425,116 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", user)
 }
+
+// UpdateMe handles profile updates
+// @Summary Update current user
+// @Description Change username, email and/or full name. Changing the email resets its verification and sends a new verification link.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateProfileRequest true "Fields to change"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /auth/me [patch]
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.UpdateProfileRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(userID.(uint), &req)
+	if err != nil {
+		if err.Error() == "username already exists" || err.Error() == "email already exists" {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update profile")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", user)
+}
+
+// DeleteMe handles account deletion
+// @Summary Delete current user
+// @Description Permanently close the account. Tasks and categories are deleted and all sessions and API keys are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DeleteAccountRequest true "Current password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /auth/me [delete]
+func (h *AuthHandler) DeleteMe(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.DeleteAccountRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.DeleteAccount(userID.(uint), &req); err != nil {
+		if errors.Is(err, utils.ErrIncorrectPassword) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrOwnsSharedWorkspaces) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account deleted successfully", nil)
+}
+
+// clientInfo extracts client details used to describe a session
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// lockoutResponse writes a 423 (account locked) or 429 (throttled) response with a
+// Retry-After header if err is a lockout error, and reports whether it did
+func lockoutResponse(c *gin.Context, err error) bool {
+	var lockErr *lockout.Error
+	if !errors.As(err, &lockErr) {
+		return false
+	}
+
+	status := http.StatusTooManyRequests
+	if errors.Is(err, lockout.ErrLocked) {
+		status = http.StatusLocked
+	}
+
+	c.Header("Retry-After", strconv.Itoa(lockErr.RetrySeconds()))
+	utils.ErrorResponse(c, status, lockErr.Error())
+	return true
+}
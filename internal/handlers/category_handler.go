@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -23,7 +24,7 @@ func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHand
 
 // Create handles category creation
 // @Summary Create a new category
-// @Description Create a new category for the authenticated user
+// @Description Create a new category for the authenticated user, or in a workspace when workspace_id is set
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -32,6 +33,7 @@ func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHand
 // @Success 201 {object} models.Category
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 409 {object} map[string]interface{}
 // @Router /categories [post]
 func (h *CategoryHandler) Create(c *gin.Context) {
@@ -48,8 +50,12 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryService.Create(&req, userID.(uint))
+	category, err := h.categoryService.Create(&req, userID.(uint), c.GetString("requestID"))
 	if err != nil {
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		if err.Error() == "category with this name already exists" {
 			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 			return
@@ -63,10 +69,11 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 
 // GetAll handles getting all categories for a user
 // @Summary Get all categories
-// @Description Get all categories for the authenticated user with pagination
+// @Description Get all personal and workspace categories of the authenticated user with pagination
 // @Tags Categories
 // @Produce json
 // @Security BearerAuth
+// @Param workspace_id query int false "Only categories of this workspace"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
 // @Success 200 {object} map[string]interface{}
@@ -83,9 +90,14 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 	// Parse pagination parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	workspaceID, _ := strconv.ParseUint(c.DefaultQuery("workspace_id", "0"), 10, 32)
 
-	categories, total, err := h.categoryService.GetAllByUser(userID.(uint), page, pageSize)
+	categories, total, err := h.categoryService.GetAllByUser(userID.(uint), uint(workspaceID), page, pageSize)
 	if err != nil {
+		if errors.Is(err, utils.ErrWorkspaceNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -140,6 +152,7 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} models.Category
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 409 {object} map[string]interface{}
 // @Router /categories/{id} [put]
@@ -164,7 +177,7 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryService.Update(uint(id), &req, userID.(uint))
+	category, err := h.categoryService.Update(uint(id), &req, userID.(uint), c.GetString("requestID"))
 	if err != nil {
 		if err.Error() == "category not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
@@ -174,6 +187,10 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -190,6 +207,7 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 // @Param id path int true "Category ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /categories/{id} [delete]
 func (h *CategoryHandler) Delete(c *gin.Context) {
@@ -207,8 +225,12 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.categoryService.Delete(uint(id), userID.(uint))
+	err = h.categoryService.Delete(uint(id), userID.(uint), c.GetString("requestID"))
 	if err != nil {
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
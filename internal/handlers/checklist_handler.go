@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type ChecklistHandler struct {
+	checklistService *services.ChecklistService
+}
+
+// NewChecklistHandler creates a new checklist handler
+func NewChecklistHandler(checklistService *services.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{
+		checklistService: checklistService,
+	}
+}
+
+// GetAll godoc
+// @Summary List checklist items
+// @Description Get the checklist of a task in order
+// @Tags checklist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {array} models.ChecklistItem
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Router /tasks/{id}/checklist [get]
+func (h *ChecklistHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	items, err := h.checklistService.GetAllByTask(uint(taskID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve checklist")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checklist retrieved successfully", items)
+}
+
+// Create godoc
+// @Summary Add a checklist item
+// @Description Add an item to the checklist of a task, at the end unless a position is given
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.CreateChecklistItemRequest true "Checklist item"
+// @Success 201 {object} models.ChecklistItem
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "Checklist is full"
+// @Router /tasks/{id}/checklist [post]
+func (h *ChecklistHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req models.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := h.checklistService.Create(uint(taskID), userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to add checklist item")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Checklist item added successfully", item)
+}
+
+// Update godoc
+// @Summary Update a checklist item
+// @Description Change the text of a checklist item or check it off
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param itemId path int true "Checklist item ID"
+// @Param request body models.UpdateChecklistItemRequest true "Checklist item update"
+// @Success 200 {object} models.ChecklistItem
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task or checklist item not found"
+// @Router /tasks/{id}/checklist/{itemId} [put]
+func (h *ChecklistHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, itemID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := h.checklistService.Update(itemID, taskID, userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update checklist item")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checklist item updated successfully", item)
+}
+
+// Delete godoc
+// @Summary Delete a checklist item
+// @Description Remove an item from the checklist of a task
+// @Tags checklist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param itemId path int true "Checklist item ID"
+// @Success 200 {object} map[string]interface{} "Checklist item deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task or checklist item not found"
+// @Router /tasks/{id}/checklist/{itemId} [delete]
+func (h *ChecklistHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, itemID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checklistService.Delete(itemID, taskID, userID.(uint)); err != nil {
+		h.handleError(c, err, "Failed to delete checklist item")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checklist item deleted successfully", nil)
+}
+
+// Reorder godoc
+// @Summary Reorder a checklist
+// @Description Put the checklist of a task in a new order. item_ids must list every item exactly once.
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.ReorderChecklistRequest true "Item IDs in their new order"
+// @Success 200 {array} models.ChecklistItem
+// @Failure 400 {object} map[string]interface{} "Validation error or incomplete order"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Router /tasks/{id}/checklist/order [put]
+func (h *ChecklistHandler) Reorder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req models.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	items, err := h.checklistService.Reorder(uint(taskID), userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to reorder checklist")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checklist reordered successfully", items)
+}
+
+// parseIDs reads the task and checklist item IDs from the URL, responding with 400 when invalid
+func (h *ChecklistHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid checklist item ID")
+		return 0, 0, false
+	}
+	return uint(taskID), uint(itemID), true
+}
+
+// handleError maps checklist service errors to HTTP responses
+func (h *ChecklistHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTaskNotFound), errors.Is(err, utils.ErrChecklistItemNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrChecklistItemEmpty), errors.Is(err, utils.ErrChecklistOrderMismatch):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrChecklistFull):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
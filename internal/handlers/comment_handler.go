@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// Create godoc
+// @Summary Add a comment
+// @Description Add a comment to a task. Everyone who can see the task may comment.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param comment body models.CreateCommentRequest true "Comment data"
+// @Success 201 {object} map[string]interface{} "Comment created successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	comment, err := h.commentService.Create(uint(taskID), userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create comment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Comment created successfully", comment)
+}
+
+// GetAll godoc
+// @Summary List comments
+// @Description Get the comments of a task, oldest first, with pagination
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Comments retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/comments [get]
+func (h *CommentHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var filter models.CommentFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	comments, total, err := h.commentService.GetAllByTask(uint(taskID), userID.(uint), filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve comments")
+		return
+	}
+
+	filter.SetDefaults()
+	utils.PaginatedResponse(c, http.StatusOK, "Comments retrieved successfully", comments, total, filter.Page, filter.PageSize)
+}
+
+// GetByID godoc
+// @Summary Get comment
+// @Description Get a comment with its edit history (previous versions, newest first)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} map[string]interface{} "Comment retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task or comment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/comments/{commentId} [get]
+func (h *CommentHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, commentID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentService.GetByID(commentID, taskID, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve comment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment retrieved successfully", comment)
+}
+
+// Update godoc
+// @Summary Edit comment
+// @Description Change the text of a comment. Only the author can edit; the previous text is kept in the edit history.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param commentId path int true "Comment ID"
+// @Param comment body models.UpdateCommentRequest true "Comment data"
+// @Success 200 {object} map[string]interface{} "Comment updated successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the author"
+// @Failure 404 {object} map[string]interface{} "Task or comment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/comments/{commentId} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, commentID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	comment, err := h.commentService.Update(commentID, taskID, userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update comment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment updated successfully", comment)
+}
+
+// Delete godoc
+// @Summary Delete comment
+// @Description Delete a comment. Only the author can delete it.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} map[string]interface{} "Comment deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the author"
+// @Failure 404 {object} map[string]interface{} "Task or comment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, commentID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(commentID, taskID, userID.(uint)); err != nil {
+		h.handleError(c, err, "Failed to delete comment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment deleted successfully", nil)
+}
+
+// parseIDs reads the task and comment IDs from the URL, responding with 400 when invalid
+func (h *CommentHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid comment ID")
+		return 0, 0, false
+	}
+	return uint(taskID), uint(commentID), true
+}
+
+// handleError maps comment service errors to HTTP responses
+func (h *CommentHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTaskNotFound), errors.Is(err, utils.ErrCommentNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrNotCommentAuthor):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
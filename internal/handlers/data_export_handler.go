@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type DataExportHandler struct {
+	exportService *services.DataExportService
+}
+
+// NewDataExportHandler creates a new data export handler
+func NewDataExportHandler(exportService *services.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Request godoc
+// @Summary Request a data export
+// @Description Start generating an archive (JSON and CSV) of the profile, categories, tasks and login history. The user is emailed when it is ready.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.DataExport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /auth/me/export [post]
+func (h *DataExportHandler) Request(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	export, err := h.exportService.RequestExport(userID.(uint))
+	if err != nil {
+		if errors.Is(err, utils.ErrDataExportInProgress) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to request data export")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Data export started", export)
+}
+
+// GetAll godoc
+// @Summary List data exports
+// @Description Get all data exports of the current user
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.DataExport
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/me/export [get]
+func (h *DataExportHandler) GetAll(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	exports, err := h.exportService.GetAllByUser(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch data exports")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data exports retrieved successfully", exports)
+}
+
+// GetByID godoc
+// @Summary Get data export status
+// @Description Get the status of a data export. Completed exports include a time-limited download link.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} models.DataExport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /auth/me/export/{id} [get]
+func (h *DataExportHandler) GetByID(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	export, err := h.exportService.GetExport(userID.(uint), uint(id))
+	if err != nil {
+		if errors.Is(err, utils.ErrDataExportNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch data export")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data export retrieved successfully", export)
+}
+
+// Download godoc
+// @Summary Download a data export
+// @Description Download the archive using the link from the export status or email. Each link works once and only for its export.
+// @Tags Authentication
+// @Produce application/zip
+// @Param id path int true "Export ID"
+// @Param token query string true "Download token"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /auth/me/export/{id}/download [get]
+func (h *DataExportHandler) Download(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	export, path, err := h.exportService.OpenDownload(uint(id), token)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidDownloadToken) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrDataExportNotReady) {
+			utils.ErrorResponse(c, http.StatusGone, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to download data export")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(path, fmt.Sprintf("data-export-%d.zip", export.ID))
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetAll godoc
+// @Summary List notifications
+// @Description Get the notifications of the authenticated user, newest first
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Filter by type (mention)"
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Notifications retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications [get]
+func (h *NotificationHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var filter models.NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	notifications, total, err := h.notificationService.GetAll(userID.(uint), filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	filter.SetDefaults()
+	utils.PaginatedResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications, total, filter.Page, filter.PageSize)
+}
+
+// GetUnreadCount godoc
+// @Summary Count unread notifications
+// @Description Get the number of unread notifications of the authenticated user
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Unread count retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	count, err := h.notificationService.GetUnreadCount(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count notifications")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unread count retrieved successfully", gin.H{"unread_count": count})
+}
+
+// MarkRead godoc
+// @Summary Mark notification as read
+// @Description Mark a notification of the authenticated user as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]interface{} "Notification marked as read"
+// @Failure 400 {object} map[string]interface{} "Invalid notification ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(uint(notificationID), userID.(uint))
+	if err != nil {
+		if errors.Is(err, utils.ErrNotificationNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to mark notification as read")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification marked as read", notification)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark all unread notifications of the authenticated user as read, optionally only those of one type
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only notifications of this type (mention)"
+// @Success 200 {object} map[string]interface{} "Notifications marked as read"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /notifications/read-all [patch]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.MarkAllReadRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(userID.(uint), req.Type)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to mark notifications as read")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications marked as read", gin.H{"updated_count": updated})
+}
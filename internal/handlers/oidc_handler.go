@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// OIDC state cookie settings. The cookie binds the callback to the browser that started
+// the login, so a callback URL from someone else's login cannot be replayed.
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/auth/oidc"
+	oidcStateCookieAge  = 600
+)
+
+type OIDCHandler struct {
+	oidcService *services.OIDCService
+}
+
+// NewOIDCHandler creates a new OIDC handler
+func NewOIDCHandler(oidcService *services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// Login godoc
+// @Summary Start single sign-on login
+// @Description Redirect to the OpenID Connect provider (authorization code flow with PKCE)
+// @Tags Authentication
+// @Success 302
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /auth/oidc/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, state, err := h.oidcService.StartLogin(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, oidcStateCookieAge, oidcStateCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary Complete single sign-on login
+// @Description Exchange the authorization code from the provider for tokens. Accounts are linked by verified email or created on first login.
+// @Tags Authentication
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State returned by the provider"
+// @Success 200 {object} models.AuthResponse
+// @Success 202 {object} models.TwoFactorChallenge
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		message := "Login was rejected by the identity provider: " + providerErr
+		if description := c.Query("error_description"); description != "" {
+			message += " (" + description + ")"
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, message)
+		return
+	}
+
+	state := c.Query("state")
+	code := c.Query("code")
+	if state == "" || code == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "code and state are required")
+		return
+	}
+
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", c.Request.TLS != nil, true)
+	if subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrInvalidOIDCState.Error())
+		return
+	}
+
+	authResponse, challenge, err := h.oidcService.HandleCallback(c.Request.Context(), state, code, clientInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if challenge != nil {
+		utils.SuccessResponse(c, http.StatusAccepted, "Two-factor authentication required", challenge)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", authResponse)
+}
+
+// handleError maps OIDC service errors to HTTP responses
+func (h *OIDCHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrOIDCNotConfigured):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidOIDCState):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrOIDCLoginFailed):
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, utils.ErrOIDCUnavailable):
+		utils.ErrorResponse(c, http.StatusBadGateway, err.Error())
+	case errors.Is(err, utils.ErrOIDCEmailNotVerified),
+		errors.Is(err, utils.ErrOIDCSignupDisabled),
+		errors.Is(err, utils.ErrAccountDisabled):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to login")
+	}
+}
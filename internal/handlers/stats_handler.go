@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"github.com/hoanghnt/TaskManagementAPI/internal/services"
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
@@ -107,3 +109,47 @@ func (h *StatsHandler) GetOverdueTasks(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Overdue tasks retrieved successfully", tasks)
 }
+
+// GetTimeReport godoc
+// @Summary Get time report
+// @Description Sum up the time tracked on visible tasks by day (UTC) or category. The range defaults to the last 30 days and cannot exceed 366 days.
+// @Tags Statistics
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD), default today"
+// @Param group_by query string false "day (default) or category"
+// @Param workspace_id query int false "Only tasks of this workspace"
+// @Param mine query bool false "Only the user's own time entries"
+// @Success 200 {object} models.TimeReport
+// @Failure 400 {object} map[string]interface{} "Invalid range"
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/time [get]
+func (h *StatsHandler) GetTimeReport(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var filter models.TimeReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Get time report
+	report, err := h.statsService.GetTimeReport(userID.(uint), filter)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidReportRange) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve time report")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time report retrieved successfully", report)
+}
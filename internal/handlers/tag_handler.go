@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// Create handles tag creation
+// @Summary Create a new tag
+// @Description Create a personal tag, or a tag of a workspace when workspace_id is set
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTagRequest true "Tag details"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Create(&req, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to create tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// GetAll handles getting all tags for a user
+// @Summary Get all tags
+// @Description Get all personal and workspace tags of the authenticated user with their task counts
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query int false "Only tags of this workspace"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /tags [get]
+func (h *TagHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	workspaceID, _ := strconv.ParseUint(c.DefaultQuery("workspace_id", "0"), 10, 32)
+
+	tags, total, err := h.tagService.GetAllByUser(userID.(uint), uint(workspaceID), page, pageSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrWorkspaceNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Tags retrieved successfully", tags, total, page, pageSize)
+}
+
+// GetByID handles getting a tag by ID
+// @Summary Get tag by ID
+// @Description Get a specific tag by ID
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Success 200 {object} models.Tag
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	tag, err := h.tagService.GetByID(uint(id), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag retrieved successfully", tag)
+}
+
+// Update handles updating a tag
+// @Summary Update tag
+// @Description Rename or recolor a tag
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Param request body models.UpdateTagRequest true "Tag update details"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	var req models.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Update(uint(id), &req, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to update tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+// Delete handles deleting a tag
+// @Summary Delete tag
+// @Description Delete a tag (soft delete) and remove it from all tasks
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	if err := h.tagService.Delete(uint(id), userID.(uint)); err != nil {
+		h.handleError(c, err, "Failed to delete tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag deleted successfully", nil)
+}
+
+// handleError maps tag service errors to HTTP responses
+func (h *TagHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTagNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrTagNameTaken):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceNotFound), errors.Is(err, utils.ErrTagNameRequired):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
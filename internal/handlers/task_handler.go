@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -23,7 +24,7 @@ func NewTaskHandler(taskService *services.TaskService) *TaskHandler {
 
 // CreateTask godoc
 // @Summary Create a new task
-// @Description Create a new task for the authenticated user
+// @Description Create a new task for the authenticated user, or in a workspace when workspace_id is set
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -32,6 +33,7 @@ func NewTaskHandler(taskService *services.TaskService) *TaskHandler {
 // @Success 201 {object} map[string]interface{} "Task created successfully"
 // @Failure 400 {object} map[string]interface{} "Validation error"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /tasks [post]
 func (h *TaskHandler) CreateTask(c *gin.Context) {
@@ -50,7 +52,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	}
 
 	// Create task
-	task, err := h.taskService.CreateTask(userID.(uint), req)
+	task, err := h.taskService.CreateTask(userID.(uint), req, c.GetString("requestID"))
 	if err != nil {
 		if err.Error() == "category not found" {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -60,6 +62,34 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrWorkspaceNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrCategoryWorkspaceMismatch) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrAssigneeNoAccess) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrParentTaskNotFound) || errors.Is(err, utils.ErrParentWorkspaceMismatch) || errors.Is(err, utils.ErrTaskDepthExceeded) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidRecurrenceRule) || errors.Is(err, utils.ErrRecurrenceNeedsDueDate) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrUnknownStatus) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create task")
 		return
 	}
@@ -69,7 +99,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 // GetAllTasks godoc
 // @Summary Get all tasks
-// @Description Get all tasks for the authenticated user with filtering, sorting, and pagination
+// @Description Get all personal and workspace tasks of the authenticated user with filtering, sorting, and pagination
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -77,7 +107,12 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 // @Param status query string false "Filter by status (pending, in_progress, completed)"
 // @Param priority query string false "Filter by priority (low, medium, high)"
 // @Param category_id query int false "Filter by category ID"
+// @Param workspace_id query int false "Filter by workspace ID"
+// @Param assignee query string false "Filter by assignee (me, none or a user ID)"
+// @Param blocked query bool false "Filter by open blockers (true or false)"
+// @Param series_id query int false "Filter by recurring task series"
 // @Param search query string false "Search in title and description"
+// @Param include_progress query bool false "Include the progress of each task's subtasks"
 // @Param sort_by query string false "Sort by field (created_at, updated_at, due_date, priority)" default(created_at)
 // @Param sort_order query string false "Sort order (asc, desc)" default(desc)
 // @Param page query int false "Page number" default(1)
@@ -109,6 +144,14 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrWorkspaceNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidAssigneeFilter) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tasks")
 		return
 	}
@@ -122,12 +165,13 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 
 // GetTaskByID godoc
 // @Summary Get task by ID
-// @Description Get a specific task by ID for the authenticated user
+// @Description Get a specific task by ID for the authenticated user, including the tasks that block it and the tasks it blocks
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Task ID"
+// @Param include_progress query bool false "Include the progress of the task's subtasks"
 // @Success 200 {object} map[string]interface{} "Task retrieved successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid task ID"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -149,8 +193,15 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 		return
 	}
 
+	// Parse query parameters
+	var query models.TaskQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
 	// Get task
-	task, err := h.taskService.GetTaskByID(uint(taskID), userID.(uint))
+	task, err := h.taskService.GetTaskByID(uint(taskID), userID.(uint), query)
 	if err != nil {
 		if err.Error() == "task not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
@@ -163,6 +214,106 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Task retrieved successfully", task)
 }
 
+// GetSubtasks godoc
+// @Summary Get subtasks
+// @Description Get the direct subtasks of a task, oldest first
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param include_progress query bool false "Include the progress of each subtask's own subtasks"
+// @Success 200 {object} map[string]interface{} "Subtasks retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid task ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/subtasks [get]
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task ID from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Parse query parameters
+	var query models.TaskQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	subtasks, err := h.taskService.GetSubtasks(uint(taskID), userID.(uint), query)
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve subtasks")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Subtasks retrieved successfully", subtasks)
+}
+
+// GetTaskHistory godoc
+// @Summary Get task history
+// @Description Get the field-level change log of a task (who changed what and when), newest first
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Task history retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/history [get]
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task ID from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Parse pagination parameters
+	var filter models.ActivityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	activities, total, err := h.taskService.GetTaskHistory(uint(taskID), userID.(uint), filter)
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve task history")
+		return
+	}
+
+	filter.SetDefaults()
+	utils.PaginatedResponse(c, http.StatusOK, "Task history retrieved successfully", activities, total, filter.Page, filter.PageSize)
+}
+
 // UpdateTask godoc
 // @Summary Update task
 // @Description Update an existing task (partial update)
@@ -175,7 +326,9 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Task updated successfully"
 // @Failure 400 {object} map[string]interface{} "Validation error"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
 // @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "Task has open subtasks or blockers, or the workflow does not allow the status change"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
@@ -201,7 +354,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	}
 
 	// Update task
-	task, err := h.taskService.UpdateTask(uint(taskID), userID.(uint), req)
+	task, err := h.taskService.UpdateTask(uint(taskID), userID.(uint), req, c.GetString("requestID"))
 	if err != nil {
 		if err.Error() == "task not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
@@ -215,6 +368,27 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrCategoryWorkspaceMismatch) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrParentTaskNotFound) || errors.Is(err, utils.ErrParentWorkspaceMismatch) ||
+			errors.Is(err, utils.ErrTaskHierarchyCycle) || errors.Is(err, utils.ErrTaskDepthExceeded) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrUnknownStatus) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrOpenSubtasks) || errors.Is(err, utils.ErrTaskBlocked) || errors.Is(err, utils.ErrIllegalTransition) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update task")
 		return
 	}
@@ -224,7 +398,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 
 // UpdateTaskStatus godoc
 // @Summary Update task status
-// @Description Update only the status of a task (quick status change)
+// @Description Move a task to a status of its workflow (quick status change)
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -234,7 +408,9 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Task status updated successfully"
 // @Failure 400 {object} map[string]interface{} "Validation error"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
 // @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "Task has open subtasks or blockers, or the workflow does not allow the status change"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /tasks/{id}/status [patch]
 func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
@@ -260,12 +436,24 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 	}
 
 	// Update status
-	task, err := h.taskService.UpdateTaskStatus(uint(taskID), userID.(uint), req.Status)
+	task, err := h.taskService.UpdateTaskStatus(uint(taskID), userID.(uint), req.Status, c.GetString("requestID"))
 	if err != nil {
 		if err.Error() == "task not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrUnknownStatus) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrOpenSubtasks) || errors.Is(err, utils.ErrTaskBlocked) || errors.Is(err, utils.ErrIllegalTransition) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update task status")
 		return
 	}
@@ -275,7 +463,7 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 
 // DeleteTask godoc
 // @Summary Delete task
-// @Description Soft delete a task
+// @Description Soft delete a task. Its subtasks are blocked, deleted or detached depending on the server configuration.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -284,7 +472,9 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Task deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid task ID"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
 // @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "Task has subtasks"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /tasks/{id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
@@ -303,11 +493,19 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	}
 
 	// Delete task
-	if err := h.taskService.DeleteTask(uint(taskID), userID.(uint)); err != nil {
+	if err := h.taskService.DeleteTask(uint(taskID), userID.(uint), c.GetString("requestID")); err != nil {
 		if err.Error() == "task not found" {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrTaskHasSubtasks) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete task")
 		return
 	}
@@ -315,6 +513,377 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Task deleted successfully", nil)
 }
 
+// AssignTask godoc
+// @Summary Assign users to a task
+// @Description Assign one or more users to a task. Personal tasks can only be assigned to their creator, workspace tasks to workspace members.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.AssignTaskRequest true "Users to assign"
+// @Success 200 {object} map[string]interface{} "Task assigned successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error or assignee cannot access the task"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/assignees [post]
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task ID from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Parse request body
+	var req models.AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Assign users
+	task, err := h.taskService.AssignTask(uint(taskID), userID.(uint), req, c.GetString("requestID"))
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrAssigneeNoAccess) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to assign task")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task assigned successfully", task)
+}
+
+// UnassignTask godoc
+// @Summary Unassign a user from a task
+// @Description Remove an assignee from a task. Assignees can always unassign themselves.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param userId path int true "Assignee user ID"
+// @Success 200 {object} map[string]interface{} "Task unassigned successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Task not found or user not assigned"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/assignees/{userId} [delete]
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task and assignee IDs from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+	assigneeID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// Unassign user
+	task, err := h.taskService.UnassignTask(uint(taskID), userID.(uint), uint(assigneeID), c.GetString("requestID"))
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) || errors.Is(err, utils.ErrAssigneeNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to unassign task")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task unassigned successfully", task)
+}
+
+// GetSeries godoc
+// @Summary Get a recurring task series
+// @Description Get the recurrence rule and state of a series; list its occurrences with GET /tasks?series_id=
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param seriesId path int true "Series ID"
+// @Success 200 {object} map[string]interface{} "Series retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid series ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Series not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/series/{seriesId} [get]
+func (h *TaskHandler) GetSeries(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse series ID from URL
+	seriesID, err := strconv.ParseUint(c.Param("seriesId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid series ID")
+		return
+	}
+
+	series, err := h.taskService.GetSeries(uint(seriesID), userID.(uint))
+	if err != nil {
+		if errors.Is(err, utils.ErrSeriesNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve series")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Series retrieved successfully", series)
+}
+
+// UpdateSeries godoc
+// @Summary Update a recurring task series
+// @Description Change the recurrence rule of a series and apply title, description, priority or category to all occurrences that are not completed
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param seriesId path int true "Series ID"
+// @Param request body models.UpdateSeriesRequest true "Series update data"
+// @Success 200 {object} map[string]interface{} "Series updated successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error or invalid recurrence rule"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Series not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/series/{seriesId} [put]
+func (h *TaskHandler) UpdateSeries(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse series ID from URL
+	seriesID, err := strconv.ParseUint(c.Param("seriesId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid series ID")
+		return
+	}
+
+	// Parse request body
+	var req models.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	series, err := h.taskService.UpdateSeries(uint(seriesID), userID.(uint), req, c.GetString("requestID"))
+	if err != nil {
+		if errors.Is(err, utils.ErrSeriesNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidRecurrenceRule) || errors.Is(err, utils.ErrCategoryNotFound) || errors.Is(err, utils.ErrCategoryWorkspaceMismatch) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update series")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Series updated successfully", series)
+}
+
+// StopSeries godoc
+// @Summary Stop a recurring task series
+// @Description Stop creating new occurrences when occurrences of the series are completed. Existing occurrences are kept.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param seriesId path int true "Series ID"
+// @Success 200 {object} map[string]interface{} "Series stopped successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid series ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Series not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/series/{seriesId}/stop [post]
+func (h *TaskHandler) StopSeries(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse series ID from URL
+	seriesID, err := strconv.ParseUint(c.Param("seriesId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid series ID")
+		return
+	}
+
+	series, err := h.taskService.StopSeries(uint(seriesID), userID.(uint))
+	if err != nil {
+		if errors.Is(err, utils.ErrSeriesNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to stop series")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Series stopped successfully", series)
+}
+
+// AddDependency godoc
+// @Summary Add a blocker to a task
+// @Description Mark a task as blocked by another task of the same workspace. Dependencies that would create a cycle are rejected.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.AddDependencyRequest true "Blocking task"
+// @Success 200 {object} map[string]interface{} "Dependency added successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error, blocking task not found, from another workspace or cycle"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/dependencies [post]
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task ID from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Parse request body
+	var req models.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	task, err := h.taskService.AddDependency(uint(taskID), userID.(uint), req, c.GetString("requestID"))
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrBlockerNotFound) || errors.Is(err, utils.ErrDependencyWorkspaceMismatch) || errors.Is(err, utils.ErrDependencyCycle) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to add dependency")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Dependency added successfully", task)
+}
+
+// RemoveDependency godoc
+// @Summary Remove a blocker from a task
+// @Description Remove the dependency of a task on a blocking task
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param blockerId path int true "Blocking task ID"
+// @Success 200 {object} map[string]interface{} "Dependency removed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace is read-only for the user"
+// @Failure 404 {object} map[string]interface{} "Task or dependency not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tasks/{id}/dependencies/{blockerId} [delete]
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse task and blocker IDs from URL
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blockerId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid blocking task ID")
+		return
+	}
+
+	task, err := h.taskService.RemoveDependency(uint(taskID), userID.(uint), uint(blockerID), c.GetString("requestID"))
+	if err != nil {
+		if errors.Is(err, utils.ErrTaskNotFound) || errors.Is(err, utils.ErrDependencyNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrWorkspaceForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove dependency")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Dependency removed successfully", task)
+}
+
 // BulkUpdateStatus godoc
 // @Summary Bulk update task status
 // @Description Update status for multiple tasks at once
@@ -324,8 +893,9 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.BulkUpdateStatusRequest true "Bulk status update data"
 // @Success 200 {object} map[string]interface{} "Bulk update completed"
-// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 400 {object} map[string]interface{} "Validation error or unknown status"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "The workflow of a task does not allow the status change"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /tasks/bulk/status [patch]
 func (h *TaskHandler) BulkUpdateStatus(c *gin.Context) {
@@ -344,8 +914,12 @@ func (h *TaskHandler) BulkUpdateStatus(c *gin.Context) {
 	}
 
 	// Bulk update
-	response, err := h.taskService.BulkUpdateStatus(userID.(uint), req)
+	response, err := h.taskService.BulkUpdateStatus(userID.(uint), req, c.GetString("requestID"))
 	if err != nil {
+		if errors.Is(err, utils.ErrIllegalTransition) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
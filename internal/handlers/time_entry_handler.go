@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type TimeEntryHandler struct {
+	timeEntryService *services.TimeEntryService
+}
+
+// NewTimeEntryHandler creates a new time entry handler
+func NewTimeEntryHandler(timeEntryService *services.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		timeEntryService: timeEntryService,
+	}
+}
+
+// GetRunning godoc
+// @Summary Get running timer
+// @Description Get the running timer of the current user; data is null when no timer is running
+// @Tags time tracking
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /tasks/timer [get]
+func (h *TimeEntryHandler) GetRunning(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	entry, err := h.timeEntryService.GetRunning(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve timer")
+		return
+	}
+	if entry == nil {
+		utils.SuccessResponse(c, http.StatusOK, "No timer is running", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Timer retrieved successfully", entry)
+}
+
+// StartTimer godoc
+// @Summary Start a timer
+// @Description Start tracking time on a task. A user can only run one timer at a time.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.StartTimerRequest false "Optional note"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "A timer is already running"
+// @Router /tasks/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// The body is optional
+	var req models.StartTimerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	entry, err := h.timeEntryService.StartTimer(uint(taskID), userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to start timer")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Timer started successfully", entry)
+}
+
+// StopTimer godoc
+// @Summary Stop a timer
+// @Description Stop the current user's running timer on a task
+// @Tags time tracking
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "No timer is running on the task"
+// @Router /tasks/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	entry, err := h.timeEntryService.StopTimer(uint(taskID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to stop timer")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Timer stopped successfully", entry)
+}
+
+// GetAll godoc
+// @Summary List time entries
+// @Description Get the time entries of a task, newest first
+// @Tags time tracking
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {array} models.TimeEntry
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Router /tasks/{id}/time-entries [get]
+func (h *TimeEntryHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	entries, err := h.timeEntryService.GetAllByTask(uint(taskID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve time entries")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entries retrieved successfully", entries)
+}
+
+// Create godoc
+// @Summary Add a time entry
+// @Description Record time spent on a task without a timer. The period must lie in the past and not overlap the user's other entries.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.CreateTimeEntryRequest true "Time entry"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Workspace viewer"
+// @Failure 404 {object} map[string]interface{} "Task not found"
+// @Failure 409 {object} map[string]interface{} "Overlaps another time entry"
+// @Router /tasks/{id}/time-entries [post]
+func (h *TimeEntryHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	entry, err := h.timeEntryService.Create(uint(taskID), userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to add time entry")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Time entry added successfully", entry)
+}
+
+// Update godoc
+// @Summary Update a time entry
+// @Description Change the period or note of your own time entry
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param entryId path int true "Time entry ID"
+// @Param request body models.UpdateTimeEntryRequest true "Time entry update"
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} map[string]interface{} "Validation error"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the owner"
+// @Failure 404 {object} map[string]interface{} "Task or time entry not found"
+// @Failure 409 {object} map[string]interface{} "Overlaps another time entry or timer still running"
+// @Router /tasks/{id}/time-entries/{entryId} [put]
+func (h *TimeEntryHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, entryID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	entry, err := h.timeEntryService.Update(entryID, taskID, userID.(uint), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update time entry")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entry updated successfully", entry)
+}
+
+// Delete godoc
+// @Summary Delete a time entry
+// @Description Delete your own time entry; deleting a running timer discards it
+// @Tags time tracking
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param entryId path int true "Time entry ID"
+// @Success 200 {object} map[string]interface{} "Time entry deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the owner"
+// @Failure 404 {object} map[string]interface{} "Task or time entry not found"
+// @Router /tasks/{id}/time-entries/{entryId} [delete]
+func (h *TimeEntryHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID, entryID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.timeEntryService.Delete(entryID, taskID, userID.(uint)); err != nil {
+		h.handleError(c, err, "Failed to delete time entry")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entry deleted successfully", nil)
+}
+
+// parseIDs reads the task and time entry IDs from the URL, responding with 400 when invalid
+func (h *TimeEntryHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return 0, 0, false
+	}
+	entryID, err := strconv.ParseUint(c.Param("entryId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid time entry ID")
+		return 0, 0, false
+	}
+	return uint(taskID), uint(entryID), true
+}
+
+// handleError maps time entry service errors to HTTP responses
+func (h *TimeEntryHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTaskNotFound), errors.Is(err, utils.ErrTimeEntryNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden), errors.Is(err, utils.ErrNotTimeEntryOwner):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrInvalidTimeEntry), errors.Is(err, utils.ErrTimeEntryInFuture):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrTimerAlreadyRunning), errors.Is(err, utils.ErrTimerNotRunning),
+		errors.Is(err, utils.ErrTimeEntryRunning), errors.Is(err, utils.ErrTimeEntryOverlap):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
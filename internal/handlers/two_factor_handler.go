@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Setup godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and otpauth:// URI. Two-factor authentication becomes active after confirming a code.
+// @Tags Two-Factor Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TwoFactorSetupResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	response, err := h.twoFactorService.Setup(userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to start two-factor setup")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the URI with your authenticator app and confirm with a code", response)
+}
+
+// Confirm godoc
+// @Summary Confirm two-factor enrollment
+// @Description Activate two-factor authentication with a first TOTP code. Returns recovery codes, shown once.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /auth/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.twoFactorService.Confirm(userID.(uint), req.Code)
+	if err != nil {
+		h.handleError(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled. Store the recovery codes now, they will not be shown again", response)
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication. Requires the password and a TOTP or recovery code.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.twoFactorService.Disable(userID.(uint), &req); err != nil {
+		h.handleError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes. Requires a TOTP code. The new codes are shown once.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.twoFactorService.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		h.handleError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes regenerated. Store them now, they will not be shown again", response)
+}
+
+// handleError maps two-factor service errors to HTTP responses
+func (h *TwoFactorHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrTwoFactorAlreadyEnabled):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, utils.ErrTwoFactorNotEnabled),
+		errors.Is(err, utils.ErrTwoFactorNotSetUp),
+		errors.Is(err, utils.ErrInvalidTwoFactorCode),
+		errors.Is(err, utils.ErrIncorrectPassword):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrUserNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type WorkflowHandler struct {
+	workflowService *services.WorkflowService
+}
+
+// NewWorkflowHandler creates a new workflow handler
+func NewWorkflowHandler(workflowService *services.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// Create handles workflow creation
+// @Summary Create a workflow
+// @Description Define the statuses of your personal tasks, or of a workspace you manage when workspace_id is set. Existing tasks keep statuses with the same key and otherwise move to the first status of their category; a status with the key of tasks' current status must keep their category.
+// @Tags Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateWorkflowRequest true "Workflow definition"
+// @Success 201 {object} models.Workflow
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /workflows [post]
+func (h *WorkflowHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CreateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	workflow, err := h.workflowService.Create(&req, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to create workflow")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Workflow created successfully", workflow)
+}
+
+// GetAll handles getting all workflows of a user
+// @Summary Get all workflows
+// @Description Get the personal workflow and the workspace workflows visible to the authenticated user
+// @Tags Workflows
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query int false "Only the workflow of this workspace"
+// @Success 200 {array} models.Workflow
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /workflows [get]
+func (h *WorkflowHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, _ := strconv.ParseUint(c.DefaultQuery("workspace_id", "0"), 10, 32)
+
+	workflows, err := h.workflowService.GetAllByUser(userID.(uint), uint(workspaceID))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workflows")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workflows retrieved successfully", workflows)
+}
+
+// GetEffective handles getting the workflow that applies to a scope
+// @Summary Get the effective workflow
+// @Description Get the workflow of your personal tasks, or of a workspace when workspace_id is set. Scopes without a workflow of their own get the default workflow (pending, in_progress, completed).
+// @Tags Workflows
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query int false "Workspace ID"
+// @Success 200 {object} models.Workflow
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /workflows/effective [get]
+func (h *WorkflowHandler) GetEffective(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaceID, _ := strconv.ParseUint(c.DefaultQuery("workspace_id", "0"), 10, 32)
+
+	workflow, err := h.workflowService.GetEffective(userID.(uint), uint(workspaceID))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workflow")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workflow retrieved successfully", workflow)
+}
+
+// GetByID handles getting a workflow by ID
+// @Summary Get workflow by ID
+// @Description Get a specific workflow with its statuses and transitions
+// @Tags Workflows
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workflow ID"
+// @Success 200 {object} models.Workflow
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workflows/{id} [get]
+func (h *WorkflowHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	workflow, err := h.workflowService.GetByID(uint(id), userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workflow")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workflow retrieved successfully", workflow)
+}
+
+// Update handles updating a workflow
+// @Summary Update workflow
+// @Description Rename a workflow or replace its statuses or transitions. Tasks in a removed status move to the first status of their category. A status that tasks are in cannot change its category.
+// @Tags Workflows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workflow ID"
+// @Param request body models.UpdateWorkflowRequest true "Workflow update"
+// @Success 200 {object} models.Workflow
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /workflows/{id} [put]
+func (h *WorkflowHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	var req models.UpdateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	workflow, err := h.workflowService.Update(uint(id), &req, userID.(uint))
+	if err != nil {
+		h.handleError(c, err, "Failed to update workflow")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workflow updated successfully", workflow)
+}
+
+// Delete handles deleting a workflow
+// @Summary Delete workflow
+// @Description Delete a workflow; its tasks go back to the default statuses of their category
+// @Tags Workflows
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workflow ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workflows/{id} [delete]
+func (h *WorkflowHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	if err := h.workflowService.Delete(uint(id), userID.(uint)); err != nil {
+		h.handleError(c, err, "Failed to delete workflow")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workflow deleted successfully", nil)
+}
+
+// handleError maps workflow service errors to HTTP responses
+func (h *WorkflowHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrWorkflowNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrWorkflowExists), errors.Is(err, utils.ErrStatusInUse):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceNotFound), errors.Is(err, utils.ErrInvalidWorkflow):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
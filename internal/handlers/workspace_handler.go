@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type WorkspaceHandler struct {
+	workspaceService *services.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(workspaceService *services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: workspaceService,
+	}
+}
+
+// Create godoc
+// @Summary Create a workspace
+// @Description Create a workspace owned by the authenticated user
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateWorkspaceRequest true "Workspace details"
+// @Success 201 {object} models.Workspace
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.Create(userID.(uint), &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Workspace created successfully", workspace)
+}
+
+// GetAll godoc
+// @Summary List workspaces
+// @Description Get the workspaces the authenticated user is a member of, with their role
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Workspace
+// @Failure 401 {object} map[string]interface{}
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) GetAll(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workspaces, err := h.workspaceService.GetAllByUser(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch workspaces")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspaces retrieved successfully", workspaces)
+}
+
+// GetByID godoc
+// @Summary Get workspace
+// @Description Get a workspace with its members
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} models.Workspace
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id} [get]
+func (h *WorkspaceHandler) GetByID(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspaceService.GetByID(id, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch workspace")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace retrieved successfully", workspace)
+}
+
+// Update godoc
+// @Summary Update workspace
+// @Description Change the name or description of a workspace (owner or admin)
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param request body models.UpdateWorkspaceRequest true "Workspace details"
+// @Success 200 {object} models.Workspace
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id} [put]
+func (h *WorkspaceHandler) Update(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.Update(id, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update workspace")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace updated successfully", workspace)
+}
+
+// Delete godoc
+// @Summary Delete workspace
+// @Description Delete a workspace together with its tasks and categories (owner only)
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id} [delete]
+func (h *WorkspaceHandler) Delete(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.Delete(id, userID); err != nil {
+		h.handleError(c, err, "Failed to delete workspace")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Workspace deleted successfully", nil)
+}
+
+// GetMembers godoc
+// @Summary List workspace members
+// @Description Get the members of a workspace with their roles
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {array} models.WorkspaceMember
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id}/members [get]
+func (h *WorkspaceHandler) GetMembers(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.GetMembers(id, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch members")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Members retrieved successfully", members)
+}
+
+// UpdateMemberRole godoc
+// @Summary Change a member's role
+// @Description Owners and admins can change members and viewers; only the owner can change admins. Setting "owner" transfers ownership.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param userId path int true "User ID of the member"
+// @Param request body models.UpdateMemberRoleRequest true "New role"
+// @Success 200 {object} models.WorkspaceMember
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id}/members/{userId} [put]
+func (h *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	member, err := h.workspaceService.UpdateMemberRole(id, userID, uint(memberID), req.Role)
+	if err != nil {
+		h.handleError(c, err, "Failed to update member role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member role updated successfully", member)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member
+// @Description Remove a member from a workspace, or leave it by passing your own user ID
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param userId path int true "User ID of the member"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /workspaces/{id}/members/{userId} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(id, userID, uint(memberID)); err != nil {
+		h.handleError(c, err, "Failed to remove member")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member removed successfully", nil)
+}
+
+// CreateInvitation godoc
+// @Summary Invite to a workspace
+// @Description Invite by email (single use, only for that address) or create a shareable link when no email is given (owner or admin). The token is only returned here.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param request body models.CreateInvitationRequest true "Invitation details"
+// @Success 201 {object} models.WorkspaceInvitation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) CreateInvitation(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	invitation, err := h.workspaceService.CreateInvitation(id, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invitation created successfully", invitation)
+}
+
+// GetInvitations godoc
+// @Summary List pending invitations
+// @Description Get the invitations of a workspace that can still be accepted (owner or admin)
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Success 200 {array} models.WorkspaceInvitation
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) GetInvitations(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.workspaceService.GetInvitations(id, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch invitations")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke an invitation
+// @Description Revoke a pending invitation (owner or admin)
+// @Tags Workspaces
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Workspace ID"
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /workspaces/{id}/invitations/{invitationId} [delete]
+func (h *WorkspaceHandler) RevokeInvitation(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.workspaceService.RevokeInvitation(id, userID, uint(invitationID)); err != nil {
+		h.handleError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Join a workspace with an invitation token. Invitations sent to an email address can only be accepted by an account that has verified that address.
+// @Tags Workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} models.Workspace
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.AcceptInvitation(userID.(uint), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation accepted successfully", workspace)
+}
+
+// parseRequest reads the authenticated user and the workspace ID, writing an error response on failure
+func (h *WorkspaceHandler) parseRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid workspace ID")
+		return 0, 0, false
+	}
+
+	return userID.(uint), uint(id), true
+}
+
+// handleError maps workspace errors to responses
+func (h *WorkspaceHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrWorkspaceNotFound),
+		errors.Is(err, utils.ErrMemberNotFound),
+		errors.Is(err, utils.ErrInvitationNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrWorkspaceForbidden),
+		errors.Is(err, utils.ErrInvitationEmailMismatch),
+		errors.Is(err, utils.ErrEmailNotVerified):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrAlreadyMember),
+		errors.Is(err, utils.ErrOwnerCannotLeave):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, utils.ErrInvalidInvitation):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,164 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/config"
+	"gorm.io/gorm"
+)
+
+// cleanupInterval is how often expired entries are deleted from trackers that do not prune themselves
+const cleanupInterval = 10 * time.Minute
+
+// expiredDeleter is implemented by trackers that keep expired entries until they are deleted
+type expiredDeleter interface {
+	DeleteExpired() (int64, error)
+}
+
+// Guard combines a per-username and a per-IP tracker for the login endpoint.
+// Username failures lead to a lockout (423), IP failures only throttle (429)
+// so an attacker cannot lock out every account from a single address.
+type Guard struct {
+	users Tracker
+	ips   Tracker
+}
+
+// NewGuard creates a new login guard
+func NewGuard(users, ips Tracker) *Guard {
+	return &Guard{users: users, ips: ips}
+}
+
+// Check returns an *Error if a login attempt for the username from the IP must be rejected
+func (g *Guard) Check(username, ip string) error {
+	status, err := g.users.Check(UserKey(username))
+	if err != nil {
+		return err
+	}
+	if blocked := blockedError(status); blocked != nil {
+		return blocked
+	}
+
+	if ip == "" {
+		return nil
+	}
+	status, err = g.ips.Check(IPKey(ip))
+	if err != nil {
+		return err
+	}
+	if status.Blocked() {
+		return &Error{Err: ErrThrottled, RetryAfter: status.RetryAfter}
+	}
+	return nil
+}
+
+// RecordFailure registers a failed login and returns the username status,
+// which tells the caller whether this failure locked the account
+func (g *Guard) RecordFailure(username, ip string) (Status, error) {
+	if ip != "" {
+		if _, err := g.ips.RecordFailure(IPKey(ip)); err != nil {
+			return Status{}, err
+		}
+	}
+	return g.users.RecordFailure(UserKey(username))
+}
+
+// RecordSuccess clears the failures of a username after a successful login.
+// IP counters are kept so one valid account cannot be used to reset them.
+func (g *Guard) RecordSuccess(username string) error {
+	return g.users.Reset(UserKey(username))
+}
+
+// Unlock lifts the lockout of a username
+func (g *Guard) Unlock(username string) error {
+	return g.users.Reset(UserKey(username))
+}
+
+// UserStatus returns the lockout status of a username
+func (g *Guard) UserStatus(username string) (Status, error) {
+	return g.users.Check(UserKey(username))
+}
+
+// Start deletes expired entries periodically until ctx is cancelled, so failures for random
+// usernames or addresses do not pile up
+func (g *Guard) Start(ctx context.Context) {
+	go g.cleanup(ctx)
+}
+
+// cleanup periodically deletes expired entries of the trackers
+func (g *Guard) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		g.deleteExpired()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpired deletes the expired entries of trackers that need it
+func (g *Guard) deleteExpired() {
+	for _, tracker := range []Tracker{g.users, g.ips} {
+		deleter, ok := tracker.(expiredDeleter)
+		if !ok {
+			continue
+		}
+		if _, err := deleter.DeleteExpired(); err != nil {
+			log.Printf("❌ Failed to delete expired login attempts: %v", err)
+		}
+	}
+}
+
+// UserKey returns the tracker key for a username
+func UserKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// IPKey returns the tracker key for an IP address
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// blockedError converts a blocked username status into an *Error
+func blockedError(status Status) error {
+	if !status.Blocked() {
+		return nil
+	}
+	if status.Locked {
+		return &Error{Err: ErrLocked, RetryAfter: status.RetryAfter}
+	}
+	return &Error{Err: ErrThrottled, RetryAfter: status.RetryAfter}
+}
+
+// New creates a login guard from configuration. Usernames are locked after
+// MaxAttempts failures; IP addresses are only throttled with backoff.
+func New(cfg *config.LockoutConfig, db *gorm.DB) (*Guard, error) {
+	userPolicy := Policy{
+		FreeAttempts:    cfg.FreeAttempts,
+		MaxAttempts:     cfg.MaxAttempts,
+		BaseDelay:       time.Duration(cfg.BackoffBaseSeconds) * time.Second,
+		MaxDelay:        time.Duration(cfg.BackoffMaxSeconds) * time.Second,
+		LockoutDuration: time.Duration(cfg.LockoutMinutes) * time.Minute,
+		Window:          time.Duration(cfg.WindowMinutes) * time.Minute,
+	}
+	ipPolicy := userPolicy
+	ipPolicy.FreeAttempts = cfg.IPFreeAttempts
+	ipPolicy.MaxAttempts = 0
+
+	switch cfg.Store {
+	case "postgres":
+		return NewGuard(NewPostgresTracker(db, userPolicy), NewPostgresTracker(db, ipPolicy)), nil
+	case "memory", "":
+		return NewGuard(NewMemoryTracker(userPolicy), NewMemoryTracker(ipPolicy)), nil
+	default:
+		return nil, fmt.Errorf("unknown login tracker store: %s", cfg.Store)
+	}
+}
@@ -0,0 +1,36 @@
+package lockout
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// deletingTracker is a tracker that counts calls to DeleteExpired
+type deletingTracker struct {
+	*MemoryTracker
+	calls int
+	err   error
+}
+
+func (t *deletingTracker) DeleteExpired() (int64, error) {
+	t.calls++
+	return 0, t.err
+}
+
+func TestGuardDeleteExpired(t *testing.T) {
+	policy := Policy{FreeAttempts: 3, MaxAttempts: 5, Window: time.Minute}
+	users := &deletingTracker{MemoryTracker: NewMemoryTracker(policy), err: errors.New("database is down")}
+	ips := &deletingTracker{MemoryTracker: NewMemoryTracker(policy)}
+
+	guard := NewGuard(users, ips)
+	guard.deleteExpired()
+
+	// A failing tracker does not stop the others from being cleaned up
+	if users.calls != 1 || ips.calls != 1 {
+		t.Errorf("DeleteExpired calls = %d (users), %d (IPs); want 1 each", users.calls, ips.calls)
+	}
+
+	// Trackers that prune themselves are skipped
+	NewGuard(NewMemoryTracker(policy), NewMemoryTracker(policy)).deleteExpired()
+}
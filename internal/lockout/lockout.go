@@ -0,0 +1,129 @@
+package lockout
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrThrottled means the caller must wait before trying again
+	ErrThrottled = errors.New("too many failed login attempts, try again later")
+	// ErrLocked means the account is temporarily locked
+	ErrLocked = errors.New("account is temporarily locked due to too many failed login attempts")
+)
+
+// Error is returned when a login attempt is blocked. It wraps ErrThrottled or ErrLocked.
+type Error struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (retry after %d seconds)", e.Err.Error(), e.RetrySeconds())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// RetrySeconds returns the wait time rounded up to whole seconds, for the Retry-After header
+func (e *Error) RetrySeconds() int {
+	seconds := int(e.RetryAfter / time.Second)
+	if e.RetryAfter%time.Second > 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// Policy describes how failed attempts are throttled and when a key gets locked
+type Policy struct {
+	FreeAttempts    int           // failures allowed before backoff starts
+	MaxAttempts     int           // failures that trigger a lockout
+	BaseDelay       time.Duration // delay after the first throttled failure, doubled on each further failure
+	MaxDelay        time.Duration // upper bound for the backoff delay
+	LockoutDuration time.Duration // how long a key stays locked
+	Window          time.Duration // failures older than this are forgotten
+}
+
+// Entry is the stored failure state of a key
+type Entry struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+// Status describes whether a key may attempt a login right now
+type Status struct {
+	Failures   int
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+// Blocked reports whether an attempt must be rejected
+func (s Status) Blocked() bool {
+	return s.RetryAfter > 0
+}
+
+// Tracker records failed login attempts per key (e.g. username or IP address)
+type Tracker interface {
+	// Check returns the current status of a key without modifying it
+	Check(key string) (Status, error)
+	// RecordFailure registers a failed attempt and returns the new status
+	RecordFailure(key string) (Status, error)
+	// Reset forgets all failures of a key and lifts any lockout
+	Reset(key string) error
+}
+
+// expired reports whether the entry no longer carries any restriction
+func (p Policy) expired(e Entry, now time.Time) bool {
+	if !e.LockedUntil.IsZero() {
+		return !now.Before(e.LockedUntil)
+	}
+	return now.Sub(e.LastFailureAt) > p.Window
+}
+
+// status evaluates an entry at the given time
+func (p Policy) status(e Entry, now time.Time) Status {
+	if e.Failures == 0 || p.expired(e, now) {
+		return Status{}
+	}
+
+	if !e.LockedUntil.IsZero() {
+		return Status{Failures: e.Failures, Locked: true, RetryAfter: e.LockedUntil.Sub(now)}
+	}
+
+	status := Status{Failures: e.Failures}
+	if e.Failures > p.FreeAttempts {
+		if next := e.LastFailureAt.Add(p.backoff(e.Failures)); now.Before(next) {
+			status.RetryAfter = next.Sub(now)
+		}
+	}
+	return status
+}
+
+// recordFailure applies a failed attempt to an entry
+func (p Policy) recordFailure(e Entry, now time.Time) Entry {
+	if e.Failures > 0 && p.expired(e, now) {
+		e = Entry{}
+	}
+
+	e.Failures++
+	e.LastFailureAt = now
+	if p.MaxAttempts > 0 && e.Failures >= p.MaxAttempts {
+		e.LockedUntil = now.Add(p.LockoutDuration)
+	}
+	return e
+}
+
+// backoff returns the delay imposed after the given number of failures
+func (p Policy) backoff(failures int) time.Duration {
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
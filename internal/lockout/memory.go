@@ -0,0 +1,72 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneInterval is the number of writes between sweeps of expired entries
+const pruneInterval = 1000
+
+// MemoryTracker keeps failed attempts in process memory.
+// It is suitable for single instance deployments; use PostgresTracker when running several instances.
+type MemoryTracker struct {
+	mu      sync.Mutex
+	policy  Policy
+	entries map[string]Entry
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryTracker creates a new in-memory tracker
+func NewMemoryTracker(policy Policy) *MemoryTracker {
+	return &MemoryTracker{
+		policy:  policy,
+		entries: make(map[string]Entry),
+		now:     time.Now,
+	}
+}
+
+// Check returns the current status of a key
+func (t *MemoryTracker) Check(key string) (Status, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.policy.status(t.entries[key], t.now()), nil
+}
+
+// RecordFailure registers a failed attempt
+func (t *MemoryTracker) RecordFailure(key string) (Status, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	entry := t.policy.recordFailure(t.entries[key], now)
+	t.entries[key] = entry
+
+	t.writes++
+	if t.writes >= pruneInterval {
+		t.prune(now)
+	}
+
+	return t.policy.status(entry, now), nil
+}
+
+// Reset forgets all failures of a key
+func (t *MemoryTracker) Reset(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+	return nil
+}
+
+// prune removes entries that no longer restrict anything. Caller must hold the lock.
+func (t *MemoryTracker) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if t.policy.expired(entry, now) {
+			delete(t.entries, key)
+		}
+	}
+	t.writes = 0
+}
@@ -0,0 +1,103 @@
+package lockout
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresTracker stores failed attempts in the login_attempts table so that
+// all API instances share the same counters.
+type PostgresTracker struct {
+	db     *gorm.DB
+	policy Policy
+}
+
+// NewPostgresTracker creates a new database backed tracker
+func NewPostgresTracker(db *gorm.DB, policy Policy) *PostgresTracker {
+	return &PostgresTracker{db: db, policy: policy}
+}
+
+// Check returns the current status of a key
+func (t *PostgresTracker) Check(key string) (Status, error) {
+	var attempt models.LoginAttempt
+	err := t.db.Where("key = ?", key).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Status{}, nil
+		}
+		return Status{}, err
+	}
+
+	return t.policy.status(toEntry(attempt), time.Now()), nil
+}
+
+// RecordFailure registers a failed attempt. The row is locked while it is updated
+// so concurrent failures from several instances are all counted.
+func (t *PostgresTracker) RecordFailure(key string) (Status, error) {
+	var status Status
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		// Make sure the row exists before locking it
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.LoginAttempt{Key: key}).Error; err != nil {
+			return err
+		}
+
+		var attempt models.LoginAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).
+			First(&attempt).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		entry := t.policy.recordFailure(toEntry(attempt), now)
+
+		attempt.Failures = entry.Failures
+		attempt.LastFailureAt = &entry.LastFailureAt
+		attempt.LockedUntil = nil
+		if !entry.LockedUntil.IsZero() {
+			attempt.LockedUntil = &entry.LockedUntil
+		}
+		if err := tx.Save(&attempt).Error; err != nil {
+			return err
+		}
+
+		status = t.policy.status(entry, now)
+		return nil
+	})
+
+	return status, err
+}
+
+// Reset forgets all failures of a key
+func (t *PostgresTracker) Reset(key string) error {
+	return t.db.Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// DeleteExpired removes rows that no longer restrict anything. The username and IP trackers
+// share the table and the window, so either one cleans up both.
+func (t *PostgresTracker) DeleteExpired() (int64, error) {
+	now := time.Now()
+	result := t.db.
+		Where("(locked_until IS NOT NULL AND locked_until <= ?) OR (locked_until IS NULL AND last_failure_at < ?)",
+			now, now.Add(-t.policy.Window)).
+		Delete(&models.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
+
+// toEntry converts a database row into a tracker entry
+func toEntry(attempt models.LoginAttempt) Entry {
+	entry := Entry{Failures: attempt.Failures}
+	if attempt.LastFailureAt != nil {
+		entry.LastFailureAt = *attempt.LastFailureAt
+	}
+	if attempt.LockedUntil != nil {
+		entry.LockedUntil = *attempt.LockedUntil
+	}
+	return entry
+}
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/config"
+)
+
+// Message represents a plain text email
+type Message struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New creates a mailer for the configured driver
+func New(cfg *config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "outbox", "":
+		return NewOutboxMailer(cfg.OutboxDir), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.Driver)
+	}
+}
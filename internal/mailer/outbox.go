@@ -0,0 +1,91 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutboxMailer keeps sent messages in memory and optionally writes them to a directory.
+// It is meant for development and tests.
+type OutboxMailer struct {
+	mu       sync.Mutex
+	dir      string
+	messages []Message
+}
+
+// NewOutboxMailer creates a new outbox mailer. If dir is empty, messages are only kept in memory.
+func NewOutboxMailer(dir string) *OutboxMailer {
+	return &OutboxMailer{dir: dir}
+}
+
+// Send stores the message in the outbox
+func (m *OutboxMailer) Send(msg Message) error {
+	msg.SentAt = time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	log.Printf("📧 Outbox: %q to %s", msg.Subject, msg.To)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.txt", msg.SentAt.Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	content := fmt.Sprintf("To: %s\nSubject: %s\nDate: %s\n\n%s\n", msg.To, msg.Subject, msg.SentAt.Format(time.RFC1123Z), msg.Body)
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far
+func (m *OutboxMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
+
+// Last returns the most recent message sent to the given address
+func (m *OutboxMailer) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset clears the in-memory outbox
+func (m *OutboxMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
+
+// sanitizeFileName replaces characters that are unsafe in file names
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
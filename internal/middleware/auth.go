@@ -1,15 +1,28 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/services"
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
 
-// AuthMiddleware validates JWT token
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware authenticates the request with either a JWT access token or an API key.
+// JWTs whose session was revoked are rejected. API keys are accepted as
+// "Authorization: Bearer tm_..." or in the X-API-Key header.
+func AuthMiddleware(sessionRepo *repository.SessionRepository, apiKeyService *services.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API key in dedicated header
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, apiKeyService, apiKey)
+			return
+		}
+
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
 
@@ -21,6 +34,12 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// API key passed as bearer token
+		if strings.HasPrefix(tokenString, models.APIKeyPrefix) {
+			authenticateAPIKey(c, apiKeyService, tokenString)
+			return
+		}
+
 		// Validate token
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
@@ -29,9 +48,148 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Check that the session is still active
+		if claims.SessionID == "" {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+		active, err := sessionRepo.IsActive(claims.SessionID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate session")
+			c.Abort()
+			return
+		}
+		if !active {
+			utils.ErrorResponse(c, http.StatusUnauthorized, utils.ErrSessionRevoked.Error())
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.SessionID)
+
+		c.Next()
+	}
+}
+
+// authenticateAPIKey validates an API key and sets the key owner in context
+func authenticateAPIKey(c *gin.Context, apiKeyService *services.APIKeyService, rawKey string) {
+	key, err := apiKeyService.Authenticate(rawKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidAPIKey):
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, utils.ErrAccountDisabled):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to validate api key")
+		}
+		c.Abort()
+		return
+	}
+
+	// Set user info in context
+	c.Set("userID", key.UserID)
+	c.Set("username", key.User.Username)
+	c.Set("role", string(key.User.Role))
+	c.Set("apiKeyID", key.ID)
+	c.Set("apiKeyScopes", key.GetScopes())
+
+	c.Next()
+}
+
+// RequireScope checks that API key requests carry the scope for the resource.
+// GET and HEAD requests need "<resource>:read", all other methods "<resource>:write".
+// Requests authenticated with a JWT are always allowed.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, isAPIKey := c.Get("apiKeyScopes")
+		if !isAPIKey {
+			c.Next()
+			return
+		}
+
+		required := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			required = resource + ":read"
+		}
+
+		for _, scope := range value.([]string) {
+			if scope == required {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, utils.ErrInsufficientScope.Error()+": "+required)
+		c.Abort()
+	}
+}
+
+// RequireSession rejects requests authenticated with an API key.
+// Used for account management endpoints that need an interactive login.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := c.Get("apiKeyScopes"); isAPIKey {
+			utils.ErrorResponse(c, http.StatusForbidden, utils.ErrAPIKeyNotAllowed.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole allows the request only if the authenticated user has one of the given roles.
+// It must run after AuthMiddleware.
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := models.UserRole(c.GetString("role"))
+
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, "Insufficient permissions")
+		c.Abort()
+	}
+}
+
+// RequireVerifiedEmail blocks users that have not verified their email address.
+// It does nothing when enabled is false.
+func RequireVerifiedEmail(userRepo *repository.UserRepository, enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		userID, exists := c.Get("userID")
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		user, err := userRepo.FindByID(userID.(uint))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		if !user.IsEmailVerified() {
+			utils.ErrorResponse(c, http.StatusForbidden, utils.ErrEmailNotVerified.Error())
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+type ActivityEntityType string
+
+const (
+	ActivityEntityTask     ActivityEntityType = "task"
+	ActivityEntityCategory ActivityEntityType = "category"
+)
+
+type ActivityAction string
+
+const (
+	ActivityActionCreated       ActivityAction = "created"
+	ActivityActionUpdated       ActivityAction = "updated"
+	ActivityActionStatusChanged ActivityAction = "status_changed"
+	ActivityActionDeleted       ActivityAction = "deleted"
+)
+
+// Activity is an entry in the audit trail of tasks and categories: who changed what and when.
+// Entries are never updated or deleted, even when the task or category is.
+type Activity struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	EntityType  ActivityEntityType `gorm:"type:varchar(20);not null;index:idx_activity_entity" json:"entity_type"`
+	EntityID    uint               `gorm:"not null;index:idx_activity_entity" json:"entity_id"`
+	EntityName  string             `gorm:"size:200" json:"entity_name"` // title or name at the time of the change
+	WorkspaceID *uint              `gorm:"index" json:"workspace_id,omitempty"`
+	Action      ActivityAction     `gorm:"type:varchar(20);not null" json:"action"`
+	UserID      uint               `gorm:"not null;index" json:"user_id"` // actor
+	User        User               `gorm:"foreignKey:UserID" json:"user"`
+	RequestID   string             `gorm:"size:64;index" json:"request_id,omitempty"`
+	Changes     []ActivityChange   `gorm:"foreignKey:ActivityID" json:"changes,omitempty"`
+	CreatedAt   time.Time          `gorm:"index" json:"created_at"`
+}
+
+// ActivityChange is the old and new value of one field changed by an activity.
+// Values are stored as text; null means the field had no value.
+type ActivityChange struct {
+	ID         uint    `gorm:"primaryKey" json:"-"`
+	ActivityID uint    `gorm:"not null;index" json:"-"`
+	Field      string  `gorm:"size:50;not null" json:"field"`
+	OldValue   *string `gorm:"type:text" json:"old_value"`
+	NewValue   *string `gorm:"type:text" json:"new_value"`
+}
+
+// ActivityFilter represents query parameters for listing activities
+type ActivityFilter struct {
+	EntityType  string `form:"entity_type" binding:"omitempty,oneof=task category"`
+	WorkspaceID uint   `form:"workspace_id"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults sets default values for pagination
+func (f *ActivityFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PageSize == 0 {
+		f.PageSize = 20
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+// AdminUserFilter represents query parameters for listing users
+type AdminUserFilter struct {
+	Search   string `form:"search"` // search in username, email and full name
+	Role     string `form:"role" binding:"omitempty,oneof=user admin"`
+	Status   string `form:"status" binding:"omitempty,oneof=active disabled"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults sets default values for pagination
+func (f *AdminUserFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PageSize == 0 {
+		f.PageSize = 20
+	}
+}
+
+// UpdateUserRoleRequest represents role change input
+type UpdateUserRoleRequest struct {
+	Role UserRole `json:"role" binding:"required,oneof=user admin"`
+}
+
+// SystemStats represents system-wide statistics for admins
+type SystemStats struct {
+	TotalUsers      int64            `json:"total_users"`
+	ActiveUsers     int64            `json:"active_users"`
+	DisabledUsers   int64            `json:"disabled_users"`
+	AdminUsers      int64            `json:"admin_users"`
+	TotalTasks      int64            `json:"total_tasks"`
+	TasksByStatus   map[string]int64 `json:"tasks_by_status"`
+	TasksByPriority map[string]int64 `json:"tasks_by_priority"`
+	TotalCategories int64            `json:"total_categories"`
+	ActiveSessions  int64            `json:"active_sessions"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix marks a bearer token as an API key rather than a JWT
+const APIKeyPrefix = "tm_"
+
+// API key scopes. Read scopes allow GET requests, write scopes allow everything else.
+const (
+	ScopeTasksRead          = "tasks:read"
+	ScopeTasksWrite         = "tasks:write"
+	ScopeCategoriesRead     = "categories:read"
+	ScopeCategoriesWrite    = "categories:write"
+	ScopeTagsRead           = "tags:read"
+	ScopeTagsWrite          = "tags:write"
+	ScopeWorkflowsRead      = "workflows:read"
+	ScopeWorkflowsWrite     = "workflows:write"
+	ScopeStatsRead          = "stats:read"
+	ScopeProfileRead        = "profile:read"
+	ScopeWorkspacesRead     = "workspaces:read"
+	ScopeWorkspacesWrite    = "workspaces:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	ScopeActivityRead       = "activity:read"
+)
+
+// APIKey represents a personal access token used by scripts and CI.
+// Only the SHA-256 hash of the key is stored; the key itself is shown once on creation.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID" json:"-"`
+	Name       string     `gorm:"not null;size:100" json:"name"`
+	Prefix     string     `gorm:"not null;size:20" json:"prefix"`
+	KeyHash    string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"not null;size:255" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Computed fields (not stored in DB)
+	ScopeList []string `gorm:"-" json:"scopes"`
+}
+
+// AfterFind populates the computed scope list
+func (k *APIKey) AfterFind(tx *gorm.DB) error {
+	k.ScopeList = k.GetScopes()
+	return nil
+}
+
+// GetScopes returns the scopes granted to the key
+func (k *APIKey) GetScopes() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// SetScopes stores the scopes granted to the key
+func (k *APIKey) SetScopes(scopes []string) {
+	k.Scopes = strings.Join(scopes, ",")
+	k.ScopeList = scopes
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (k *APIKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
+
+// CreateAPIKeyRequest represents API key creation input
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write categories:read categories:write tags:read tags:write workflows:read workflows:write stats:read profile:read workspaces:read workspaces:write notifications:read notifications:write activity:read"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// CreateAPIKeyResponse represents a newly created API key. Key is only returned once.
+type CreateAPIKeyResponse struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Attachment is a file uploaded to a task. The content lives in the blob store under
+// StorageKey; its size counts against the quota of the uploader.
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `gorm:"not null;index" json:"task_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"uploaded_by"`
+	FileName    string    `gorm:"not null;size:255" json:"file_name"`
+	ContentType string    `gorm:"not null;size:100" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Checksum    string    `gorm:"not null;size:64" json:"checksum"` // SHA-256, hex encoded
+	StorageKey  string    `gorm:"not null;size:255;uniqueIndex" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
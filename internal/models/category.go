@@ -13,6 +13,7 @@ type Category struct {
 	Color       string         `gorm:"size:7" json:"color"`
 	UserID      uint           `gorm:"not null" json:"user_id"`
 	User        User           `gorm:"foreignKey:UserID" json:"-"`
+	WorkspaceID *uint          `gorm:"index" json:"workspace_id,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -29,6 +30,7 @@ type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required,max=100"`
 	Description string `json:"description" binding:"max=255"`
 	Color       string `json:"color" binding:"omitempty,len=7"` // #RRGGBB format
+	WorkspaceID *uint  `json:"workspace_id"`                    // omit for a personal category
 }
 
 // UpdateCategoryRequest represents category update input
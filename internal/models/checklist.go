@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ChecklistItem is a small step of a task that is checked off without being a subtask.
+// Items are ordered by Position, which runs from 0 without gaps.
+type ChecklistItem struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TaskID    uint       `gorm:"not null;index" json:"task_id"`
+	Text      string     `gorm:"not null;size:500" json:"text"`
+	Done      bool       `gorm:"not null;default:false" json:"done"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+	Position  int        `gorm:"not null" json:"position"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ChecklistProgress counts the checklist items of a task and how many of them are done
+type ChecklistProgress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
+
+// CreateChecklistItemRequest represents checklist item creation input
+type CreateChecklistItemRequest struct {
+	Text     string `json:"text" binding:"required,max=500"`
+	Position *int   `json:"position" binding:"omitempty,min=0"` // omit to append at the end
+}
+
+// UpdateChecklistItemRequest represents checklist item update input
+type UpdateChecklistItemRequest struct {
+	Text string `json:"text" binding:"omitempty,max=500"`
+	Done *bool  `json:"done"`
+}
+
+// ReorderChecklistRequest lists every item of a checklist in its new order
+type ReorderChecklistRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment is a message in the discussion thread of a task
+type Comment struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	TaskID    uint           `gorm:"not null;index" json:"task_id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	User      User           `gorm:"foreignKey:UserID" json:"author"`
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	EditedAt  *time.Time     `json:"edited_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Edits     []CommentEdit  `gorm:"foreignKey:CommentID" json:"edits,omitempty"`
+}
+
+// CommentEdit keeps the previous text of a comment each time it is edited
+type CommentEdit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `gorm:"not null;index" json:"-"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `json:"edited_at"`
+}
+
+// CreateCommentRequest represents comment creation input
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// UpdateCommentRequest represents comment update input
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// CommentFilter represents query parameters for listing comments
+type CommentFilter struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults sets default values for pagination
+func (f *CommentFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PageSize == 0 {
+		f.PageSize = 20
+	}
+}
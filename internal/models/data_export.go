@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusCompleted  DataExportStatus = "completed"
+	DataExportStatusFailed     DataExportStatus = "failed"
+	DataExportStatusExpired    DataExportStatus = "expired"
+)
+
+// DataExport represents an asynchronously generated archive of a user's personal data
+type DataExport struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	UserID      uint             `gorm:"not null;index" json:"user_id"`
+	Status      DataExportStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	FilePath    string           `gorm:"size:255" json:"-"`
+	FileSize    int64            `json:"file_size,omitempty"`
+	Error       string           `gorm:"size:255" json:"error,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+
+	// Computed fields (not stored in DB)
+	DownloadURL       string     `gorm:"-" json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `gorm:"-" json:"download_expires_at,omitempty"`
+}
+
+// IsActive reports whether the export is still being generated
+func (e *DataExport) IsActive() bool {
+	return e.Status == DataExportStatusPending || e.Status == DataExportStatusProcessing
+}
+
+// IsDownloadable reports whether the archive is ready and has not expired
+func (e *DataExport) IsDownloadable() bool {
+	return e.Status == DataExportStatusCompleted && e.ExpiresAt != nil && time.Now().Before(*e.ExpiresAt)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LoginAttempt stores failed login attempts for a key such as "user:<username>" or "ip:<address>".
+// Used by the Postgres backed lockout tracker.
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey;size:150" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LockedUntil   *time.Time `gorm:"index" json:"locked_until,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
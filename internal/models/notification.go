@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypeMention NotificationType = "mention"
+)
+
+// Notification is an entry in a user's inbox
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null;index" json:"-"` // recipient
+	Type      NotificationType `gorm:"type:varchar(30);not null" json:"type"`
+	ActorID   *uint            `json:"actor_id,omitempty"`
+	Actor     *User            `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	TaskID    *uint            `json:"task_id,omitempty"`
+	CommentID *uint            `json:"comment_id,omitempty"`
+	Message   string           `gorm:"size:255;not null" json:"message"`
+	ReadAt    *time.Time       `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// NotificationFilter represents query parameters for listing notifications
+type NotificationFilter struct {
+	Type     string `form:"type" binding:"omitempty,oneof=mention"`
+	Unread   bool   `form:"unread"` // only unread notifications
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// MarkAllReadRequest represents query parameters for marking all notifications as read
+type MarkAllReadRequest struct {
+	Type string `form:"type" binding:"omitempty,oneof=mention"` // only notifications of this type
+}
+
+// SetDefaults sets default values for pagination
+func (f *NotificationFilter) SetDefaults() {
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PageSize == 0 {
+		f.PageSize = 20
+	}
+}
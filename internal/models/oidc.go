@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an OpenID Connect provider
+type UserIdentity struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"-"`
+	Issuer      string     `gorm:"not null;size:255;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"not null;size:255;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	Email       string     `gorm:"size:100" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OIDCLoginState holds the PKCE verifier and nonce of a pending OIDC login.
+// It is keyed by the SHA-256 hash of the state parameter and consumed by the callback.
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey;size:64" json:"-"`
+	CodeVerifier string    `gorm:"not null;size:128" json:"-"`
+	Nonce        string    `gorm:"not null;size:64" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
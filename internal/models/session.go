@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Session represents a login session. Every access token carries the session ID,
+// so revoking the session invalidates all tokens issued for it.
+type Session struct {
+	ID         string     `gorm:"primaryKey;size:64" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID" json:"-"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`
+	IPAddress  string     `gorm:"size:45" json:"ip_address"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// RefreshToken represents a one-time-use refresh token belonging to a session.
+// Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SessionID string     `gorm:"not null;size:64;index" json:"session_id"`
+	Session   Session    `gorm:"foreignKey:SessionID" json:"-"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RefreshTokenRequest represents refresh token input
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ClientInfo describes the client that opened a session
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
@@ -8,6 +8,23 @@ type TaskStats struct {
 	ByCategory     []CategoryTaskCount `json:"by_category"`
 	CompletionRate float64             `json:"completion_rate"`
 	OverdueTasks   int64               `json:"overdue_tasks"`
+	AssignedToMe   int64               `json:"assigned_to_me"`
+	AssignedOpen   int64               `json:"assigned_to_me_open"` // assigned to me and not completed
+
+	TotalPoints      int64            `json:"total_points"`
+	CompletedPoints  int64            `json:"completed_points"`
+	RemainingMinutes int64            `json:"remaining_minutes"` // remaining effort of the open tasks
+	Estimates        EstimateAccuracy `json:"estimates"`
+}
+
+// EstimateAccuracy compares the estimates of completed tasks with the time tracked on them.
+// Only completed tasks with an estimate and tracked time are included.
+type EstimateAccuracy struct {
+	TaskCount        int64   `json:"task_count"`
+	EstimatedMinutes int64   `json:"estimated_minutes"`
+	ActualMinutes    int64   `json:"actual_minutes"`
+	ActualToEstimate float64 `json:"actual_to_estimate"` // above 1 when tasks took longer than estimated
+	Accuracy         float64 `json:"accuracy"`           // average of min(estimate, actual) / max(estimate, actual), in percent
 }
 
 // CategoryTaskCount represents task count per category
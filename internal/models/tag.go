@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagMatch decides whether tasks filtered by several tags need any or all of them
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+// Tag labels tasks of the same scope: personal tags are used on the user's personal
+// tasks, workspace tags on the tasks of that workspace. Names are unique per scope
+// regardless of case.
+type Tag struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"not null;size:50" json:"name"`
+	Color       string         `gorm:"size:7" json:"color"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	WorkspaceID *uint          `gorm:"index" json:"workspace_id,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Computed fields (not stored in DB)
+	TaskCount int64 `gorm:"-" json:"task_count,omitempty"`
+}
+
+// CreateTagRequest represents tag creation input
+type CreateTagRequest struct {
+	Name        string `json:"name" binding:"required,max=50,excludesall=0x2C"`
+	Color       string `json:"color" binding:"omitempty,len=7"` // #RRGGBB format
+	WorkspaceID *uint  `json:"workspace_id"`                    // omit for a personal tag
+}
+
+// UpdateTagRequest represents tag update input
+type UpdateTagRequest struct {
+	Name  string `json:"name" binding:"omitempty,max=50,excludesall=0x2C"`
+	Color string `json:"color" binding:"omitempty,len=7"`
+}
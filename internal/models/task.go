@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -22,63 +23,196 @@ const (
 	TaskPriorityHigh   TaskPriority = "high"
 )
 
+// SubtaskBehavior decides what happens to the subtasks of a task that is completed or deleted
+type SubtaskBehavior string
+
+const (
+	SubtaskBehaviorBlock   SubtaskBehavior = "block"   // refuse while subtasks are open (complete) or exist (delete)
+	SubtaskBehaviorCascade SubtaskBehavior = "cascade" // complete or delete all subtasks as well
+	SubtaskBehaviorDetach  SubtaskBehavior = "detach"  // turn the open subtasks into top-level tasks
+)
+
 type Task struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Title       string         `gorm:"not null;size:200" json:"title"`
-	Description string         `gorm:"type:text" json:"description"`
-	Status      TaskStatus     `gorm:"type:varchar(20);default:'pending'" json:"status"`
-	Priority    TaskPriority   `gorm:"type:varchar(20);default:'medium'" json:"priority"`
-	DueDate     *time.Time     `json:"due_date,omitempty"`
-	UserID      uint           `gorm:"not null" json:"user_id"`
-	User        User           `gorm:"foreignKey:UserID" json:"-"`
-	CategoryID  *uint          `json:"category_id,omitempty"`
-	Category    *Category      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Title            string         `gorm:"not null;size:200" json:"title"`
+	Description      string         `gorm:"type:text" json:"description"`
+	Status           TaskStatus     `gorm:"type:varchar(20);default:'pending'" json:"status"` // category of the workflow status
+	WorkflowStatus   string         `gorm:"size:50;index" json:"workflow_status"`             // key of the status in the task's workflow
+	Priority         TaskPriority   `gorm:"type:varchar(20);default:'medium'" json:"priority"`
+	DueDate          *time.Time     `json:"due_date,omitempty"`
+	EstimateMinutes  *int           `json:"estimate_minutes,omitempty"`
+	RemainingMinutes *int           `json:"remaining_minutes,omitempty"` // the estimate counts until the remaining effort is updated
+	StoryPoints      *int           `json:"story_points,omitempty"`
+	UserID           uint           `gorm:"not null" json:"user_id"`
+	User             User           `gorm:"foreignKey:UserID" json:"-"`
+	WorkspaceID      *uint          `gorm:"index" json:"workspace_id,omitempty"`
+	ParentID         *uint          `gorm:"index" json:"parent_id,omitempty"`
+	SeriesID         *uint          `gorm:"index" json:"series_id,omitempty"`
+	Series           *TaskSeries    `gorm:"foreignKey:SeriesID" json:"series,omitempty"`
+	CategoryID       *uint          `json:"category_id,omitempty"`
+	Category         *Category      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Assignees        []TaskAssignee `gorm:"foreignKey:TaskID" json:"assignees,omitempty"`
+	Tags             []Tag          `gorm:"many2many:task_tags" json:"tags,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Computed fields (not stored in DB)
+	CommentCount int64              `gorm:"-" json:"comment_count"`
+	Progress     *SubtaskProgress   `gorm:"-" json:"subtask_progress,omitempty"`
+	Dependencies *TaskDependencies  `gorm:"-" json:"dependencies,omitempty"`
+	Checklist    *ChecklistProgress `gorm:"-" json:"checklist_progress,omitempty"` // only for tasks with a checklist
+	TimeSpent    *TimeSpent         `gorm:"-" json:"time_spent,omitempty"`
+}
+
+// SubtaskProgress counts the direct subtasks of a task and how many of them are completed
+type SubtaskProgress struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
+}
+
+// TaskAssignee makes a user responsible for a task. Personal tasks can only be assigned
+// to their creator, workspace tasks to members of the workspace.
+type TaskAssignee struct {
+	ID           uint      `gorm:"primaryKey" json:"-"`
+	TaskID       uint      `gorm:"not null;uniqueIndex:idx_task_assignee" json:"-"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_task_assignee;index" json:"user_id"`
+	User         User      `gorm:"foreignKey:UserID" json:"user"`
+	AssignedByID uint      `gorm:"not null" json:"assigned_by_id"`
+	CreatedAt    time.Time `json:"assigned_at"`
+}
+
+// TaskSeries links the occurrences of a recurring task. Completing the latest occurrence
+// creates the next one from the rule until the series ends or is stopped.
+type TaskSeries struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Rule        string     `gorm:"not null;size:255" json:"rule"` // RFC 5545 RRULE, e.g. FREQ=WEEKLY;BYDAY=MO
+	StartAt     time.Time  `gorm:"not null" json:"start_at"`      // due date of the first occurrence (DTSTART)
+	UserID      uint       `gorm:"not null;index" json:"-"`       // creator, owner of personal series
+	WorkspaceID *uint      `gorm:"index" json:"workspace_id,omitempty"`
+	StoppedAt   *time.Time `json:"stopped_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TaskDependency records that a task is blocked by another task of the same workspace
+// (or another personal task of the same user)
+type TaskDependency struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	TaskID      uint      `gorm:"not null;uniqueIndex:idx_task_dependency" json:"task_id"`
+	BlockerID   uint      `gorm:"not null;uniqueIndex:idx_task_dependency;index" json:"blocker_id"`
+	CreatedByID uint      `gorm:"not null" json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TaskDependencies lists the tasks that block a task and the tasks it blocks
+type TaskDependencies struct {
+	BlockedBy []TaskReference `json:"blocked_by"`
+	Blocking  []TaskReference `json:"blocking"`
+}
+
+// TaskReference is a short representation of a related task
+type TaskReference struct {
+	ID     uint       `json:"id"`
+	Title  string     `json:"title"`
+	Status TaskStatus `json:"status"`
 }
 
 // CreateTaskRequest represents task creation input
 type CreateTaskRequest struct {
 	Title       string       `json:"title" binding:"required,max=200"`
 	Description string       `json:"description"`
-	Status      TaskStatus   `json:"status" binding:"omitempty,oneof=pending in_progress completed"`
+	Status      string       `json:"status" binding:"omitempty,max=50"` // workflow status key, defaults to the first todo status
 	Priority    TaskPriority `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *time.Time   `json:"due_date"`
 	CategoryID  *uint        `json:"category_id"`
+	WorkspaceID *uint        `json:"workspace_id"` // omit for a personal task
+	ParentID    *uint        `json:"parent_id"`    // creates a subtask in the parent's workspace
+	AssigneeIDs []uint       `json:"assignee_ids"`
+	Tags        []string     `json:"tags" binding:"omitempty,max=20,dive,required,max=50,excludesall=0x2C"` // tag names, missing tags are created
+
+	EstimateMinutes *int `json:"estimate_minutes" binding:"omitempty,min=0,max=525600"`
+	StoryPoints     *int `json:"story_points" binding:"omitempty,min=0,max=1000"`
+
+	// RecurrenceRule makes the task the first occurrence of a series, e.g. "FREQ=WEEKLY;BYDAY=MO".
+	// Requires a due date.
+	RecurrenceRule string `json:"recurrence_rule" binding:"omitempty,max=255"`
 }
 
 // UpdateTaskRequest represents task update input
 type UpdateTaskRequest struct {
 	Title       string       `json:"title" binding:"omitempty,max=200"`
 	Description string       `json:"description"`
-	Status      TaskStatus   `json:"status" binding:"omitempty,oneof=pending in_progress completed"`
+	Status      string       `json:"status" binding:"omitempty,max=50"` // workflow status key
 	Priority    TaskPriority `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *time.Time   `json:"due_date"`
 	CategoryID  *uint        `json:"category_id"`
+	ParentID    *uint        `json:"parent_id"` // set to 0 to make the task a top-level task
+
+	EstimateMinutes  *int `json:"estimate_minutes" binding:"omitempty,min=0,max=525600"`  // set to 0 to remove the estimate
+	StoryPoints      *int `json:"story_points" binding:"omitempty,min=0,max=1000"`        // set to 0 to remove the story points
+	RemainingMinutes *int `json:"remaining_minutes" binding:"omitempty,min=0,max=525600"` // 0 means no work is left
+
+	// Tags replaces the tags of the task when present; an empty list removes all of them
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,required,max=50,excludesall=0x2C"`
 }
 
 // UpdateTaskStatusRequest represents task status update input
 type UpdateTaskStatusRequest struct {
-	Status TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed"`
+	Status string `json:"status" binding:"required,max=50"` // workflow status key
 }
 
 // TaskFilter represents query parameters for filtering tasks
 type TaskFilter struct {
-	Status     string `form:"status" binding:"omitempty,oneof=pending in_progress completed"`
-	Priority   string `form:"priority" binding:"omitempty,oneof=low medium high"`
-	CategoryID uint   `form:"category_id"`
-	Search     string `form:"search"` // search in title and description
-	SortBy     string `form:"sort_by" binding:"omitempty,oneof=created_at updated_at due_date priority"`
-	SortOrder  string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
-	Page       int    `form:"page" binding:"omitempty,min=1"`
-	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Status           string `form:"status" binding:"omitempty,oneof=pending in_progress completed"`
+	WorkflowStatus   string `form:"workflow_status"` // workflow status key
+	Priority         string `form:"priority" binding:"omitempty,oneof=low medium high"`
+	CategoryID       uint   `form:"category_id"`
+	WorkspaceID      uint   `form:"workspace_id"`
+	Assignee         string `form:"assignee"` // "me", "none" or a user ID
+	Blocked          *bool  `form:"blocked"`  // tasks with (true) or without (false) open blockers
+	SeriesID         uint   `form:"series_id"`
+	HasOpenChecklist *bool  `form:"has_open_checklist"`                          // tasks with (true) or without (false) unchecked checklist items
+	Tag              string `form:"tag"`                                         // comma separated tag names
+	TagMatch         string `form:"tag_match" binding:"omitempty,oneof=any all"` // tasks with any (default) or all of the tags
+	Search           string `form:"search"`                                      // search in title and description
+	SortBy           string `form:"sort_by" binding:"omitempty,oneof=created_at updated_at due_date priority"`
+	SortOrder        string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Page             int    `form:"page" binding:"omitempty,min=1"`
+	PageSize         int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+
+	IncludeProgress bool `form:"include_progress"` // add subtask progress to each task
+}
+
+// TaskQuery represents query parameters for retrieving a single task or its subtasks
+type TaskQuery struct {
+	IncludeProgress bool `form:"include_progress"`
+}
+
+// AssignTaskRequest represents task assignment input
+type AssignTaskRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1,max=20"`
+}
+
+// UpdateSeriesRequest represents recurring task series update input. The fields are applied
+// to the series and to its occurrences that are not completed yet.
+type UpdateSeriesRequest struct {
+	RecurrenceRule string       `json:"recurrence_rule" binding:"omitempty,max=255"`
+	Title          string       `json:"title" binding:"omitempty,max=200"`
+	Description    string       `json:"description"`
+	Priority       TaskPriority `json:"priority" binding:"omitempty,oneof=low medium high"`
+	CategoryID     *uint        `json:"category_id"` // set to 0 to remove the category
+}
+
+// AddDependencyRequest represents task dependency input
+type AddDependencyRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required"`
 }
 
 // BulkUpdateStatusRequest represents bulk status update input
 type BulkUpdateStatusRequest struct {
-	TaskIDs []uint     `json:"task_ids" binding:"required,min=1"`
-	Status  TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed"`
+	TaskIDs []uint `json:"task_ids" binding:"required,min=1"`
+	Status  string `json:"status" binding:"required,max=50"` // workflow status key
 }
 
 // BulkUpdateResponse represents bulk operation response
@@ -104,3 +238,18 @@ func (f *TaskFilter) SetDefaults() {
 		f.SortOrder = "desc"
 	}
 }
+
+// TagNames returns the distinct lowercased tag names of the tag filter
+func (f *TaskFilter) TagNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(f.Tag, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
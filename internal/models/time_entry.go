@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// TimeEntry records time a user spent on a task, either tracked with a timer or entered
+// manually. A running timer has no EndedAt; each user has at most one, and the entries of
+// a user never overlap.
+type TimeEntry struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	TaskID          uint       `gorm:"not null;index" json:"task_id"`
+	UserID          uint       `gorm:"not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL" json:"user_id"`
+	User            User       `gorm:"foreignKey:UserID" json:"user"`
+	StartedAt       time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`                                   // nil while the timer is running
+	DurationSeconds int64      `gorm:"not null;default:0" json:"duration_seconds"` // set once the entry has ended
+	Note            string     `gorm:"size:500" json:"note"`
+	Manual          bool       `gorm:"not null;default:false" json:"manual"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Running reports whether the entry is a timer that has not been stopped
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// TimeSpent sums up the time tracked on a task
+type TimeSpent struct {
+	TotalSeconds int64 `json:"total_seconds"` // ended entries and the elapsed time of running timers
+	EntryCount   int64 `json:"entry_count"`
+	Running      int64 `json:"running_timers"`
+}
+
+// StartTimerRequest represents timer start input
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+// CreateTimeEntryRequest represents manual time entry input
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Note      string    `json:"note" binding:"omitempty,max=500"`
+}
+
+// UpdateTimeEntryRequest represents time entry update input. Only ended entries can be changed.
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note" binding:"omitempty,max=500"`
+}
+
+// TimeReportGroup decides how the time report is grouped
+type TimeReportGroup string
+
+const (
+	TimeReportGroupDay      TimeReportGroup = "day"
+	TimeReportGroupCategory TimeReportGroup = "category"
+)
+
+// TimeReportFilter represents query parameters for the time report
+type TimeReportFilter struct {
+	From        string `form:"from" binding:"omitempty,datetime=2006-01-02"` // first day, default 29 days before to
+	To          string `form:"to" binding:"omitempty,datetime=2006-01-02"`   // last day, default today
+	GroupBy     string `form:"group_by" binding:"omitempty,oneof=day category"`
+	WorkspaceID uint   `form:"workspace_id"`
+	Mine        bool   `form:"mine"` // only the user's own entries
+}
+
+// TimeReport sums up the ended time entries of the tasks visible to a user by day or category
+type TimeReport struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	GroupBy      TimeReportGroup `json:"group_by"`
+	TotalSeconds int64           `json:"total_seconds"`
+	Rows         []TimeReportRow `json:"rows"`
+}
+
+// TimeReportRow is one day or category of the time report. Time on tasks without a category
+// is reported in a category row without category_id.
+type TimeReportRow struct {
+	Day          string `json:"day,omitempty"`
+	CategoryID   *uint  `json:"category_id,omitempty"`
+	CategoryName string `json:"category_name,omitempty"`
+	TotalSeconds int64  `json:"total_seconds"`
+	EntryCount   int64  `json:"entry_count"`
+}
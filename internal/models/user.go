@@ -7,16 +7,29 @@ import (
 	"gorm.io/gorm"
 )
 
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Username  string         `gorm:"unique;not null;size:50" json:"username"`
-	Email     string         `gorm:"unique;not null;size:100" json:"email"`
-	Password  string         `gorm:"not null" json:"-"` // "-" means don't include in JSON
-	FullName  string         `gorm:"size:100" json:"full_name"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Tasks     []Task         `gorm:"foreignKey:UserID" json:"tasks,omitempty"`
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Username           string         `gorm:"unique;not null;size:50" json:"username"`
+	Email              string         `gorm:"unique;not null;size:100" json:"email"`
+	Password           string         `gorm:"not null" json:"-"` // "-" means don't include in JSON
+	FullName           string         `gorm:"size:100" json:"full_name"`
+	Role               UserRole       `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+	DisabledAt         *time.Time     `json:"disabled_at,omitempty"`
+	TOTPSecret         string         `gorm:"size:64" json:"-"`
+	TOTPLastCounter    int64          `json:"-"`
+	TwoFactorEnabledAt *time.Time     `json:"two_factor_enabled_at,omitempty"`
+	EmailVerifiedAt    *time.Time     `json:"email_verified_at"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+	Tasks              []Task         `gorm:"foreignKey:UserID" json:"tasks,omitempty"`
 }
 
 // HashPassword hashes the user's password
@@ -35,6 +48,26 @@ func (u *User) CheckPassword(password string) bool {
 	return err == nil
 }
 
+// IsEmailVerified reports whether the user has confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsAdmin reports whether the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsDisabled reports whether the account was disabled by an admin
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsTwoFactorEnabled reports whether TOTP two-factor authentication is active
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
 // RegisterRequest represents registration input
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
@@ -49,8 +82,22 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// UpdateProfileRequest represents profile update input. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	Username *string `json:"username" binding:"omitempty,min=3,max=50"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	FullName *string `json:"full_name" binding:"omitempty,min=1,max=100"`
+}
+
+// DeleteAccountRequest represents account deletion input
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	User         User      `json:"user"`
 }
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposeTwoFactorLogin    TokenPurpose = "two_factor_login"
+	TokenPurposeAccountUnlock     TokenPurpose = "account_unlock"
+	TokenPurposeDataExport        TokenPurpose = "data_export"
+)
+
+// UserToken represents a single-use, expiring token sent to a user by email.
+// Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	UserID    uint         `gorm:"not null;index" json:"user_id"`
+	User      User         `gorm:"foreignKey:UserID" json:"-"`
+	Purpose   TokenPurpose `gorm:"type:varchar(30);not null;index" json:"purpose"`
+	TokenHash string       `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time    `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	Attempts  int          `gorm:"not null;default:0" json:"-"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ForgotPasswordRequest represents password reset request input
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents password reset input
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangePasswordRequest represents password change input
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// UnlockAccountRequest represents account unlock input
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
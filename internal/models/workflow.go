@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// StatusCategory groups the statuses of a workflow. Stats, blockers, subtasks and recurring
+// tasks only look at the category, which is stored as the task status.
+type StatusCategory string
+
+const (
+	StatusCategoryTodo  StatusCategory = "todo"
+	StatusCategoryDoing StatusCategory = "doing"
+	StatusCategoryDone  StatusCategory = "done"
+)
+
+// StatusCategories lists the categories in the order tasks move through them
+var StatusCategories = []StatusCategory{StatusCategoryTodo, StatusCategoryDoing, StatusCategoryDone}
+
+// TaskStatus returns the task status that stands for the category
+func (c StatusCategory) TaskStatus() TaskStatus {
+	switch c {
+	case StatusCategoryDoing:
+		return TaskStatusInProgress
+	case StatusCategoryDone:
+		return TaskStatusCompleted
+	default:
+		return TaskStatusPending
+	}
+}
+
+// Workflow defines the statuses of the tasks of a workspace, or of a user's personal tasks,
+// and the moves allowed between them. Each scope has at most one workflow; scopes without
+// one use the default workflow with the statuses pending, in_progress and completed.
+type Workflow struct {
+	ID          uint                 `gorm:"primaryKey" json:"id"`
+	Name        string               `gorm:"not null;size:100" json:"name"`
+	UserID      uint                 `gorm:"not null;index;uniqueIndex:idx_workflows_personal,where:workspace_id IS NULL" json:"user_id"`
+	WorkspaceID *uint                `gorm:"uniqueIndex" json:"workspace_id,omitempty"`
+	Statuses    []WorkflowStatus     `gorm:"foreignKey:WorkflowID" json:"statuses"`
+	Transitions []WorkflowTransition `gorm:"foreignKey:WorkflowID" json:"transitions"` // empty allows every move
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+
+	// Computed fields (not stored in DB)
+	Default bool `gorm:"-" json:"default"`
+}
+
+// WorkflowStatus is a status tasks of a workflow can have
+type WorkflowStatus struct {
+	ID         uint           `gorm:"primaryKey" json:"-"`
+	WorkflowID uint           `gorm:"not null;uniqueIndex:idx_workflow_status_key" json:"-"`
+	Key        string         `gorm:"not null;size:50;uniqueIndex:idx_workflow_status_key" json:"key"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	Category   StatusCategory `gorm:"type:varchar(10);not null" json:"category"`
+	Position   int            `gorm:"not null;default:0" json:"position"`
+}
+
+// WorkflowTransition allows tasks of a workflow to move from one status to another
+type WorkflowTransition struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	WorkflowID uint   `gorm:"not null;index" json:"-"`
+	From       string `gorm:"column:from_status;not null;size:50" json:"from"`
+	To         string `gorm:"column:to_status;not null;size:50" json:"to"`
+}
+
+// DefaultWorkflow returns the workflow of scopes that have not defined their own
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Name: "Default",
+		Statuses: []WorkflowStatus{
+			{Key: string(TaskStatusPending), Name: "Pending", Category: StatusCategoryTodo, Position: 0},
+			{Key: string(TaskStatusInProgress), Name: "In progress", Category: StatusCategoryDoing, Position: 1},
+			{Key: string(TaskStatusCompleted), Name: "Completed", Category: StatusCategoryDone, Position: 2},
+		},
+		Transitions: []WorkflowTransition{},
+		Default:     true,
+	}
+}
+
+// Status returns the status with the key, nil when the workflow has no such status
+func (w *Workflow) Status(key string) *WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Key == key {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// FirstStatus returns the first status of a category, where new, reopened or cascaded tasks go
+func (w *Workflow) FirstStatus(category StatusCategory) *WorkflowStatus {
+	var first *WorkflowStatus
+	for i := range w.Statuses {
+		if w.Statuses[i].Category == category && (first == nil || w.Statuses[i].Position < first.Position) {
+			first = &w.Statuses[i]
+		}
+	}
+	return first
+}
+
+// Allows reports whether a task may move between two statuses. Staying in a status is always
+// allowed, and so is leaving a status the workflow does not know.
+func (w *Workflow) Allows(from, to string) bool {
+	if len(w.Transitions) == 0 || from == to || w.Status(from) == nil {
+		return true
+	}
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkflowStatusInput describes a status of a workflow
+type WorkflowStatusInput struct {
+	Key      string         `json:"key" binding:"required,max=50"`    // lowercase letters, digits and underscores
+	Name     string         `json:"name" binding:"omitempty,max=100"` // defaults to the key
+	Category StatusCategory `json:"category" binding:"required,oneof=todo doing done"`
+}
+
+// WorkflowTransitionInput describes an allowed move between two statuses
+type WorkflowTransitionInput struct {
+	From string `json:"from" binding:"required,max=50"`
+	To   string `json:"to" binding:"required,max=50"`
+}
+
+// CreateWorkflowRequest represents workflow creation input
+type CreateWorkflowRequest struct {
+	Name        string                    `json:"name" binding:"required,max=100"`
+	WorkspaceID *uint                     `json:"workspace_id"` // omit for the personal workflow
+	Statuses    []WorkflowStatusInput     `json:"statuses" binding:"required,min=3,max=20,dive"`
+	Transitions []WorkflowTransitionInput `json:"transitions" binding:"omitempty,max=200,dive"` // omit to allow every move
+}
+
+// UpdateWorkflowRequest represents workflow update input
+type UpdateWorkflowRequest struct {
+	Name string `json:"name" binding:"omitempty,max=100"`
+
+	// Statuses and Transitions replace the current ones when present; an empty list of
+	// transitions allows every move
+	Statuses    []WorkflowStatusInput     `json:"statuses" binding:"omitempty,min=3,max=20,dive"`
+	Transitions []WorkflowTransitionInput `json:"transitions" binding:"omitempty,max=200,dive"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleMember WorkspaceRole = "member"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+// EditableWorkspaceRoles are the roles that may create and change tasks and categories
+var EditableWorkspaceRoles = []WorkspaceRole{WorkspaceRoleOwner, WorkspaceRoleAdmin, WorkspaceRoleMember}
+
+// CanEdit reports whether the role may change tasks and categories of the workspace
+func (r WorkspaceRole) CanEdit() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleAdmin || r == WorkspaceRoleMember
+}
+
+// CanManage reports whether the role may manage members and invitations
+func (r WorkspaceRole) CanManage() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleAdmin
+}
+
+// Workspace groups categories and tasks shared by a team
+type Workspace struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"not null;size:100" json:"name"`
+	Description string         `gorm:"size:255" json:"description"`
+	OwnerID     uint           `gorm:"not null;index" json:"owner_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Computed fields (not stored in DB)
+	Role        WorkspaceRole     `gorm:"-" json:"role,omitempty"`
+	MemberCount int64             `gorm:"-" json:"member_count,omitempty"`
+	Members     []WorkspaceMember `gorm:"-" json:"members,omitempty"`
+}
+
+// WorkspaceMember gives a user a role in a workspace
+type WorkspaceMember struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint          `gorm:"not null;uniqueIndex:idx_workspace_member" json:"workspace_id"`
+	UserID      uint          `gorm:"not null;uniqueIndex:idx_workspace_member;index" json:"user_id"`
+	User        User          `gorm:"foreignKey:UserID" json:"user"`
+	Role        WorkspaceRole `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt   time.Time     `json:"joined_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// WorkspaceInvitation lets someone join a workspace with a role.
+// Email invitations are single-use and only valid for the invited address; link
+// invitations can be used by anyone holding the link until they expire or are revoked.
+// Only the SHA-256 hash of the token is stored.
+type WorkspaceInvitation struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint          `gorm:"not null;index" json:"workspace_id"`
+	Email       string        `gorm:"size:100" json:"email,omitempty"`
+	Role        WorkspaceRole `gorm:"type:varchar(20);not null" json:"role"`
+	TokenHash   string        `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	InvitedByID uint          `gorm:"not null" json:"invited_by_id"`
+	UseCount    int           `gorm:"not null;default:0" json:"use_count"`
+	ExpiresAt   time.Time     `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time    `json:"accepted_at,omitempty"`
+	RevokedAt   *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+
+	// Computed fields (not stored in DB)
+	Token     string `gorm:"-" json:"token,omitempty"`
+	InviteURL string `gorm:"-" json:"invite_url,omitempty"`
+}
+
+// IsLink reports whether the invitation is a shareable link rather than addressed to an email
+func (i *WorkspaceInvitation) IsLink() bool {
+	return i.Email == ""
+}
+
+// IsPending reports whether the invitation can still be accepted
+func (i *WorkspaceInvitation) IsPending() bool {
+	if i.RevokedAt != nil || time.Now().After(i.ExpiresAt) {
+		return false
+	}
+	return i.IsLink() || i.AcceptedAt == nil
+}
+
+// CreateWorkspaceRequest represents workspace creation input
+type CreateWorkspaceRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// UpdateWorkspaceRequest represents workspace update input
+type UpdateWorkspaceRequest struct {
+	Name        string `json:"name" binding:"omitempty,max=100"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+// UpdateMemberRoleRequest represents a change of a member's role.
+// Granting "owner" transfers ownership; the previous owner becomes an admin.
+type UpdateMemberRoleRequest struct {
+	Role WorkspaceRole `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
+
+// CreateInvitationRequest represents invitation input. Without an email a shareable link is created.
+type CreateInvitationRequest struct {
+	Email string        `json:"email" binding:"omitempty,email,max=100"`
+	Role  WorkspaceRole `json:"role" binding:"omitempty,oneof=admin member viewer"`
+}
+
+// AcceptInvitationRequest represents invitation acceptance input
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,236 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hoanghnt/TaskManagementAPI/internal/config"
+)
+
+// Signing algorithms accepted for ID tokens
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+// Endpoints are the provider URLs used by the authorization code flow
+type Endpoints struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// TokenResponse is the provider's answer to a code exchange
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// IDTokenClaims holds the ID token claims used to identify the user
+type IDTokenClaims struct {
+	Email             string   `json:"email"`
+	EmailVerified     flexBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Nonce             string   `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// Provider talks to an OpenID Connect provider. Endpoints configured explicitly are used as is;
+// the rest are discovered from the issuer's /.well-known/openid-configuration on first use.
+type Provider struct {
+	cfg    *config.OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *Endpoints
+	keys      *keySet
+}
+
+// NewProvider creates a new OIDC provider client
+func NewProvider(cfg *config.OIDCConfig) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Issuer returns the configured issuer URL
+func (p *Provider) Issuer() string {
+	return strings.TrimSuffix(p.cfg.IssuerURL, "/")
+}
+
+// AuthCodeURL builds the authorization URL the user is redirected to
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(endpoints.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return endpoints.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades an authorization code and its PKCE verifier for tokens
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&oauthErr)
+		return nil, fmt.Errorf("token endpoint returned status %d: %s %s", resp.StatusCode, oauthErr.Error, oauthErr.ErrorDescription)
+	}
+
+	var tokens TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response does not contain an id_token")
+	}
+	return &tokens, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.get(ctx, kid)
+	},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(endpoints.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce does not match")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+	return claims, nil
+}
+
+// discover resolves the provider endpoints once
+func (p *Provider) discover(ctx context.Context) (*Endpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	endpoints := &Endpoints{
+		Issuer:                p.cfg.IssuerURL,
+		AuthorizationEndpoint: p.cfg.AuthURL,
+		TokenEndpoint:         p.cfg.TokenURL,
+		JWKSURI:               p.cfg.JWKSURL,
+	}
+
+	if endpoints.AuthorizationEndpoint == "" || endpoints.TokenEndpoint == "" || endpoints.JWKSURI == "" {
+		var discovered Endpoints
+		if err := getJSON(ctx, p.client, p.Issuer()+"/.well-known/openid-configuration", &discovered); err != nil {
+			return nil, fmt.Errorf("oidc discovery failed: %w", err)
+		}
+		if strings.TrimSuffix(discovered.Issuer, "/") != p.Issuer() {
+			return nil, fmt.Errorf("oidc discovery returned issuer %q, expected %q", discovered.Issuer, p.cfg.IssuerURL)
+		}
+		// Keep the issuer exactly as the provider spells it, it must match the iss claim
+		endpoints.Issuer = discovered.Issuer
+		if endpoints.AuthorizationEndpoint == "" {
+			endpoints.AuthorizationEndpoint = discovered.AuthorizationEndpoint
+		}
+		if endpoints.TokenEndpoint == "" {
+			endpoints.TokenEndpoint = discovered.TokenEndpoint
+		}
+		if endpoints.JWKSURI == "" {
+			endpoints.JWKSURI = discovered.JWKSURI
+		}
+	}
+
+	if endpoints.AuthorizationEndpoint == "" || endpoints.TokenEndpoint == "" || endpoints.JWKSURI == "" {
+		return nil, errors.New("oidc provider endpoints are incomplete")
+	}
+
+	p.endpoints = endpoints
+	p.keys = &keySet{url: endpoints.JWKSURI, client: p.client}
+	return endpoints, nil
+}
+
+// flexBool accepts both JSON booleans and the strings "true"/"false",
+// since some providers send email_verified as a string
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
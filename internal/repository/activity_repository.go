@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository creates a new activity repository
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// CreateBatch creates activities together with their field changes
+func (r *ActivityRepository) CreateBatch(activities []models.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	return r.db.Create(&activities).Error
+}
+
+// FindByEntity finds the activities of a task or category, newest first, with pagination
+func (r *ActivityRepository) FindByEntity(entityType models.ActivityEntityType, entityID uint, page, pageSize int) ([]models.Activity, int64, error) {
+	query := r.db.Model(&models.Activity{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID)
+	return r.paginate(query, page, pageSize)
+}
+
+// FindFeed finds the activities on personal records of a user and on the workspaces the user
+// belongs to, newest first, with filtering and pagination
+func (r *ActivityRepository) FindFeed(userID uint, filter models.ActivityFilter) ([]models.Activity, int64, error) {
+	query := r.db.Model(&models.Activity{}).Scopes(visibleTo("activities", userID))
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.WorkspaceID > 0 {
+		query = query.Where("activities.workspace_id = ?", filter.WorkspaceID)
+	}
+	return r.paginate(query, filter.Page, filter.PageSize)
+}
+
+// paginate counts and loads a page of activities with their actor and changes
+func (r *ActivityRepository) paginate(query *gorm.DB, page, pageSize int) ([]models.Activity, int64, error) {
+	var activities []models.Activity
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").
+		Preload("Changes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&activities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create creates a new attachment
+func (r *AttachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+// FindByID finds an attachment of a task with its uploader
+func (r *AttachmentRepository) FindByID(id uint, taskID uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Preload("User").
+		Where("id = ? AND task_id = ?", id, taskID).
+		First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// FindAllByTask finds the attachments of a task, oldest first
+func (r *AttachmentRepository) FindAllByTask(taskID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Preload("User").
+		Where("task_id = ?", taskID).
+		Order("created_at ASC, id ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// Delete removes an attachment record
+func (r *AttachmentRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Attachment{}, id).Error
+}
+
+// SumSizeByUser returns the total size in bytes of the attachments a user uploaded to tasks
+// that are not deleted
+func (r *AttachmentRepository) SumSizeByUser(userID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Attachment{}).
+		Joins("JOIN tasks ON tasks.id = attachments.task_id AND tasks.deleted_at IS NULL").
+		Where("attachments.user_id = ?", userID).
+		Select("COALESCE(SUM(attachments.size), 0)").
+		Scan(&total).Error
+	return total, err
+}
@@ -21,10 +21,13 @@ func (r *CategoryRepository) Create(category *models.Category) error {
 	return r.db.Create(category).Error
 }
 
-// FindByID finds a category by ID for a specific user
+// FindByID finds a category by ID that is visible to a specific user
 func (r *CategoryRepository) FindByID(id uint, userID uint) (*models.Category, error) {
 	var category models.Category
-	err := r.db.Preload("User").Where("id = ? AND user_id = ?", id, userID).First(&category).Error
+	err := r.db.Preload("User").
+		Scopes(visibleTo("categories", userID)).
+		Where("categories.id = ?", id).
+		First(&category).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
@@ -34,13 +37,14 @@ func (r *CategoryRepository) FindByID(id uint, userID uint) (*models.Category, e
 	return &category, nil
 }
 
-// FindAllByUser finds all categories for a specific user with pagination
-func (r *CategoryRepository) FindAllByUser(userID uint, page, pageSize int) ([]models.Category, int64, error) {
+// FindAllByUser finds all categories visible to a specific user with pagination.
+// A non-zero workspaceID limits the result to that workspace.
+func (r *CategoryRepository) FindAllByUser(userID uint, workspaceID uint, page, pageSize int) ([]models.Category, int64, error) {
 	var categories []models.Category
 	var total int64
 
 	// Count total items
-	if err := r.db.Model(&models.Category{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := r.visibleQuery(userID, workspaceID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -48,8 +52,8 @@ func (r *CategoryRepository) FindAllByUser(userID uint, page, pageSize int) ([]m
 	offset := (page - 1) * pageSize
 
 	// Get paginated results
-	err := r.db.Preload("User").
-		Where("user_id = ?", userID).
+	err := r.visibleQuery(userID, workspaceID).
+		Preload("User").
 		Order("created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -62,14 +66,24 @@ func (r *CategoryRepository) FindAllByUser(userID uint, page, pageSize int) ([]m
 	return categories, total, nil
 }
 
+// FindAllByUserWithDeleted finds every category of a user including soft deleted ones
+func (r *CategoryRepository) FindAllByUserWithDeleted(userID uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
 // Update updates a category
 func (r *CategoryRepository) Update(category *models.Category) error {
 	return r.db.Save(category).Error
 }
 
-// Delete soft deletes a category
+// Delete soft deletes a category the user may edit
 func (r *CategoryRepository) Delete(id uint, userID uint) error {
-	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Category{})
+	result := r.db.Scopes(editableBy("categories", userID)).Where("categories.id = ?", id).Delete(&models.Category{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -79,40 +93,45 @@ func (r *CategoryRepository) Delete(id uint, userID uint) error {
 	return nil
 }
 
-// ExistsByID checks if a category exists for a specific user
+// ExistsByID checks if a category exists and is visible to a specific user
 func (r *CategoryRepository) ExistsByID(id uint, userID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Category{}).
-		Where("id = ? AND user_id = ?", id, userID).
+		Scopes(visibleTo("categories", userID)).
+		Where("categories.id = ?", id).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// ExistsByNameAndUser checks if a category with the same name exists for a user
-func (r *CategoryRepository) ExistsByNameAndUser(name string, userID uint) (bool, error) {
+// ExistsByNameAndUser checks if a category with the same name exists in a workspace,
+// or among the user's personal categories when workspaceID is nil
+func (r *CategoryRepository) ExistsByNameAndUser(name string, userID uint, workspaceID *uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Category{}).
-		Where("name = ? AND user_id = ?", name, userID).
+		Scopes(inWorkspace("categories", userID, workspaceID)).
+		Where("name = ?", name).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// ExistsByNameAndUserExcludingID checks if a category with the same name exists for a user, excluding a specific ID
-func (r *CategoryRepository) ExistsByNameAndUserExcludingID(name string, userID uint, excludeID uint) (bool, error) {
+// ExistsByNameAndUserExcludingID checks if a category with the same name exists in the same scope, excluding a specific ID
+func (r *CategoryRepository) ExistsByNameAndUserExcludingID(name string, userID uint, workspaceID *uint, excludeID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Category{}).
-		Where("name = ? AND user_id = ? AND id != ?", name, userID, excludeID).
+		Scopes(inWorkspace("categories", userID, workspaceID)).
+		Where("name = ? AND id != ?", name, excludeID).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// GetCategoriesWithTaskCount retrieves categories with task counts
-func (r *CategoryRepository) GetCategoriesWithTaskCount(userID uint, page, pageSize int) ([]models.Category, int64, error) {
+// GetCategoriesWithTaskCount retrieves categories visible to a user with task counts.
+// A non-zero workspaceID limits the result to that workspace.
+func (r *CategoryRepository) GetCategoriesWithTaskCount(userID uint, workspaceID uint, page, pageSize int) ([]models.Category, int64, error) {
 	var categories []models.Category
 	var total int64
 
 	// Count total items
-	if err := r.db.Model(&models.Category{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := r.visibleQuery(userID, workspaceID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -120,8 +139,8 @@ func (r *CategoryRepository) GetCategoriesWithTaskCount(userID uint, page, pageS
 	offset := (page - 1) * pageSize
 
 	// Get categories
-	err := r.db.Preload("User").
-		Where("user_id = ?", userID).
+	err := r.visibleQuery(userID, workspaceID).
+		Preload("User").
 		Order("created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -151,3 +170,12 @@ func (r *CategoryRepository) GetCategoriesWithTaskCount(userID uint, page, pageS
 
 	return categories, total, nil
 }
+
+// visibleQuery builds a query for categories visible to the user, optionally limited to one workspace
+func (r *CategoryRepository) visibleQuery(userID uint, workspaceID uint) *gorm.DB {
+	query := r.db.Model(&models.Category{}).Scopes(visibleTo("categories", userID))
+	if workspaceID > 0 {
+		query = query.Where("categories.workspace_id = ?", workspaceID)
+	}
+	return query
+}
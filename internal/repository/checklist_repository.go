@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type ChecklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository creates a new checklist repository
+func NewChecklistRepository(db *gorm.DB) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+// Create inserts an item at its position, moving the items from that position down
+func (r *ChecklistRepository) Create(item *models.ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ChecklistItem{}).
+			Where("task_id = ? AND position >= ?", item.TaskID, item.Position).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Create(item).Error
+	})
+}
+
+// FindByID finds a checklist item of a task
+func (r *ChecklistRepository) FindByID(id uint, taskID uint) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := r.db.Where("id = ? AND task_id = ?", id, taskID).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("checklist item not found")
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindAllByTask finds the checklist items of a task in order
+func (r *ChecklistRepository) FindAllByTask(taskID uint) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	err := r.db.Where("task_id = ?", taskID).
+		Order("position ASC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// CountByTask counts the checklist items of a task
+func (r *ChecklistRepository) CountByTask(taskID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ChecklistItem{}).Where("task_id = ?", taskID).Count(&count).Error
+	return count, err
+}
+
+// Update saves the text and done state of an item
+func (r *ChecklistRepository) Update(item *models.ChecklistItem) error {
+	return r.db.Model(item).
+		Select("text", "done", "done_at").
+		Updates(item).Error
+}
+
+// Delete removes an item and closes the gap it leaves in the order
+func (r *ChecklistRepository) Delete(item *models.ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ChecklistItem{}, item.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ChecklistItem{}).
+			Where("task_id = ? AND position > ?", item.TaskID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// Reorder gives the items of a task the positions of their IDs in the list
+func (r *ChecklistRepository) Reorder(taskID uint, itemIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range itemIDs {
+			if err := tx.Model(&models.ChecklistItem{}).
+				Where("id = ? AND task_id = ?", id, taskID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create creates a new comment
+func (r *CommentRepository) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+// FindByID finds a comment of a task with its author and edit history
+func (r *CommentRepository) FindByID(id uint, taskID uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Preload("User").
+		Preload("Edits", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC, id DESC")
+		}).
+		Where("id = ? AND task_id = ?", id, taskID).
+		First(&comment).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindAllByTask finds the comments of a task, oldest first, with pagination
+func (r *CommentRepository) FindAllByTask(taskID uint, page, pageSize int) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := r.db.Model(&models.Comment{}).Where("task_id = ?", taskID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").
+		Order("created_at ASC, id ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// UpdateBody changes the text of a comment and records the previous text in its edit history
+func (r *CommentRepository) UpdateBody(comment *models.Comment, body string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		edit := models.CommentEdit{CommentID: comment.ID, Body: comment.Body}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Comment{}).
+			Where("id = ?", comment.ID).
+			Updates(map[string]interface{}{"body": body, "edited_at": now}).Error; err != nil {
+			return err
+		}
+
+		comment.Body = body
+		comment.EditedAt = &now
+		return nil
+	})
+}
+
+// Delete soft deletes a comment
+func (r *CommentRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Comment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
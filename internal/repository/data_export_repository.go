@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository creates a new data export repository
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// Create creates a new data export
+func (r *DataExportRepository) Create(export *models.DataExport) error {
+	return r.db.Create(export).Error
+}
+
+// FindByID finds a data export of a user
+func (r *DataExportRepository) FindByID(id uint, userID uint) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("data export not found")
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindAllByUser finds all data exports of a user, newest first
+func (r *DataExportRepository) FindAllByUser(userID uint) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&exports).Error
+	return exports, err
+}
+
+// HasActive checks if the user has an export that is still being generated
+func (r *DataExportRepository) HasActive(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status IN ?", userID, []models.DataExportStatus{models.DataExportStatusPending, models.DataExportStatusProcessing}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindUnfinishedIDs returns exports that were queued or running, oldest first
+func (r *DataExportRepository) FindUnfinishedIDs() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.DataExport{}).
+		Where("status IN ?", []models.DataExportStatus{models.DataExportStatusPending, models.DataExportStatusProcessing}).
+		Order("created_at ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindExpired finds completed exports whose archive should be removed
+func (r *DataExportRepository) FindExpired() ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.Where("status = ? AND expires_at < ?", models.DataExportStatusCompleted, time.Now()).
+		Find(&exports).Error
+	return exports, err
+}
+
+// ClaimPending moves a pending or interrupted export to processing.
+// It returns the export, or nil if it no longer needs to be processed.
+func (r *DataExportRepository) ClaimPending(id uint) (*models.DataExport, error) {
+	result := r.db.Model(&models.DataExport{}).
+		Where("id = ? AND status IN ?", id, []models.DataExportStatus{models.DataExportStatusPending, models.DataExportStatusProcessing}).
+		Update("status", models.DataExportStatusProcessing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var export models.DataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Update updates a data export
+func (r *DataExportRepository) Update(export *models.DataExport) error {
+	return r.db.Save(export).Error
+}
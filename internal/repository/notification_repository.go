@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateBatch creates several notifications at once
+func (r *NotificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+// FindAllByUser finds the notifications of a user, newest first, with filtering and pagination
+func (r *NotificationRepository) FindAllByUser(userID uint, filter models.NotificationFilter) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Limit(filter.PageSize).
+		Offset(offset).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread counts the unread notifications of a user
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindByID finds a notification of a user
+func (r *NotificationRepository) FindByID(id uint, userID uint) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Preload("Actor").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&notification).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkRead marks a notification as read; already read notifications keep their read time
+func (r *NotificationRepository) MarkRead(id uint, userID uint) error {
+	return r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now()).Error
+}
+
+// MarkAllRead marks all unread notifications of a user as read, optionally only those of one type
+func (r *NotificationRepository) MarkAllRead(userID uint, notificationType string) (int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if notificationType != "" {
+		query = query.Where("type = ?", notificationType)
+	}
+
+	result := query.Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OIDCRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCRepository creates a new OIDC repository
+func NewOIDCRepository(db *gorm.DB) *OIDCRepository {
+	return &OIDCRepository{db: db}
+}
+
+// CreateState stores a pending login
+func (r *OIDCRepository) CreateState(state *models.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState deletes a pending login and returns it if it has not expired.
+// Deleting first guarantees a state can only be used once.
+func (r *OIDCRepository) ConsumeState(stateHash string) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	err := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 || time.Now().After(states[0].ExpiresAt) {
+		return nil, errors.New("login state not found")
+	}
+	return &states[0], nil
+}
+
+// DeleteExpiredStates removes abandoned logins
+func (r *OIDCRepository) DeleteExpiredStates() error {
+	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error
+}
+
+// FindIdentity finds the identity for a provider subject together with its user
+func (r *OIDCRepository) FindIdentity(issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Preload("User").
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("identity not found")
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateIdentity links a provider subject to a user
+func (r *OIDCRepository) CreateIdentity(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// TouchIdentity records a login through an identity and refreshes its email
+func (r *OIDCRepository) TouchIdentity(id uint, email string) error {
+	return r.db.Model(&models.UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_login_at": time.Now(), "email": email}).Error
+}
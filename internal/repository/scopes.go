@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+// visibleTo limits a query on a table with user_id and workspace_id columns (tasks,
+// categories, activities) to the user's personal records and those of workspaces the user belongs to
+func visibleTo(table string, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			fmt.Sprintf("((%[1]s.workspace_id IS NULL AND %[1]s.user_id = ?) OR %[1]s.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?))", table),
+			userID, userID,
+		)
+	}
+}
+
+// editableBy is like visibleTo but only includes workspaces where the user's role may change content
+func editableBy(table string, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			fmt.Sprintf("((%[1]s.workspace_id IS NULL AND %[1]s.user_id = ?) OR %[1]s.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ? AND role IN ?))", table),
+			userID, userID, models.EditableWorkspaceRoles,
+		)
+	}
+}
+
+// inWorkspace limits a query to one workspace, or to the user's personal records when workspaceID is nil
+func inWorkspace(table string, userID uint, workspaceID *uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if workspaceID == nil {
+			return db.Where(fmt.Sprintf("%[1]s.workspace_id IS NULL AND %[1]s.user_id = ?", table), userID)
+		}
+		return db.Where(fmt.Sprintf("%s.workspace_id = ?", table), *workspaceID)
+	}
+}
+
+// assignedTo limits a task query to tasks assigned to the user
+func assignedTo(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tasks.id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", userID)
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *SessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID finds a session by ID
+func (r *SessionRepository) FindByID(id string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// IsActive checks if a session exists, is not revoked and has not expired
+func (r *SessionRepository) IsActive(id string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Touch updates the last used timestamp of a session
+func (r *SessionRepository) Touch(id string) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+// Revoke revokes a single session
+func (r *SessionRepository) Revoke(id string) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllByUser revokes every active session of a user
+func (r *SessionRepository) RevokeAllByUser(userID uint) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// RevokeAllByUserExcept revokes every active session of a user except the given one
+func (r *SessionRepository) RevokeAllByUserExcept(userID uint, keepID string) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND id != ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// FindAllByUser finds every session of a user, newest first
+func (r *SessionRepository) FindAllByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *SessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindRefreshTokenByHash finds a refresh token by its hash
+func (r *SessionRepository) FindRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Preload("Session").Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed marks a refresh token as used.
+// It returns false if the token had already been used (e.g. by a concurrent request).
+func (r *SessionRepository) MarkRefreshTokenUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -16,7 +16,7 @@ func NewStatsRepository(db *gorm.DB) *StatsRepository {
 	return &StatsRepository{db: db}
 }
 
-// GetTaskStats retrieves comprehensive task statistics for a user
+// GetTaskStats retrieves comprehensive statistics for the tasks visible to a user
 func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 	stats := &models.TaskStats{
 		ByStatus:   make(map[string]int64),
@@ -25,7 +25,7 @@ func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 
 	// 1. Get total tasks count
 	if err := r.db.Model(&models.Task{}).
-		Where("user_id = ?", userID).
+		Scopes(visibleTo("tasks", userID)).
 		Count(&stats.TotalTasks).Error; err != nil {
 		return nil, err
 	}
@@ -38,7 +38,7 @@ func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 	var statusCounts []StatusCount
 	if err := r.db.Model(&models.Task{}).
 		Select("status, COUNT(*) as count").
-		Where("user_id = ?", userID).
+		Scopes(visibleTo("tasks", userID)).
 		Group("status").
 		Scan(&statusCounts).Error; err != nil {
 		return nil, err
@@ -55,7 +55,7 @@ func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 	var priorityCounts []PriorityCount
 	if err := r.db.Model(&models.Task{}).
 		Select("priority, COUNT(*) as count").
-		Where("user_id = ?", userID).
+		Scopes(visibleTo("tasks", userID)).
 		Group("priority").
 		Scan(&priorityCounts).Error; err != nil {
 		return nil, err
@@ -69,7 +69,7 @@ func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 	if err := r.db.Model(&models.Task{}).
 		Select("categories.id as category_id, categories.name as category_name, COUNT(tasks.id) as task_count").
 		Joins("LEFT JOIN categories ON tasks.category_id = categories.id").
-		Where("tasks.user_id = ?", userID).
+		Scopes(visibleTo("tasks", userID)).
 		Where("categories.id IS NOT NULL").
 		Group("categories.id, categories.name").
 		Order("task_count DESC").
@@ -87,11 +87,65 @@ func (r *StatsRepository) GetTaskStats(userID uint) (*models.TaskStats, error) {
 	// 6. Get overdue tasks count
 	now := time.Now()
 	if err := r.db.Model(&models.Task{}).
-		Where("user_id = ? AND due_date < ? AND status != ?", userID, now, models.TaskStatusCompleted).
+		Scopes(visibleTo("tasks", userID)).
+		Where("due_date < ? AND status != ?", now, models.TaskStatusCompleted).
 		Count(&stats.OverdueTasks).Error; err != nil {
 		return nil, err
 	}
 
+	// 7. Get tasks assigned to the user
+	if err := r.db.Model(&models.Task{}).
+		Scopes(visibleTo("tasks", userID), assignedTo(userID)).
+		Count(&stats.AssignedToMe).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&models.Task{}).
+		Scopes(visibleTo("tasks", userID), assignedTo(userID)).
+		Where("status != ?", models.TaskStatusCompleted).
+		Count(&stats.AssignedOpen).Error; err != nil {
+		return nil, err
+	}
+
+	// 8. Sum up story points and the remaining effort of open tasks
+	type EffortSum struct {
+		TotalPoints      int64
+		CompletedPoints  int64
+		RemainingMinutes int64
+	}
+	var effort EffortSum
+	if err := r.db.Model(&models.Task{}).
+		Select("COALESCE(SUM(story_points), 0) AS total_points, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN story_points END), 0) AS completed_points, "+
+			"COALESCE(SUM(CASE WHEN status <> ? THEN COALESCE(remaining_minutes, estimate_minutes) END), 0) AS remaining_minutes",
+			models.TaskStatusCompleted, models.TaskStatusCompleted).
+		Scopes(visibleTo("tasks", userID)).
+		Scan(&effort).Error; err != nil {
+		return nil, err
+	}
+	stats.TotalPoints = effort.TotalPoints
+	stats.CompletedPoints = effort.CompletedPoints
+	stats.RemainingMinutes = effort.RemainingMinutes
+
+	// 9. Compare the estimates of completed tasks with their tracked time
+	actuals := r.db.Model(&models.Task{}).
+		Select("tasks.estimate_minutes AS estimated, SUM(time_entries.duration_seconds) / 60.0 AS actual").
+		Joins("JOIN time_entries ON time_entries.task_id = tasks.id AND time_entries.ended_at IS NOT NULL").
+		Scopes(visibleTo("tasks", userID)).
+		Where("tasks.status = ? AND tasks.estimate_minutes > 0", models.TaskStatusCompleted).
+		Group("tasks.id, tasks.estimate_minutes").
+		Having("SUM(time_entries.duration_seconds) > 0")
+	if err := r.db.Table("(?) AS actuals", actuals).
+		Select("COUNT(*) AS task_count, " +
+			"COALESCE(SUM(estimated), 0) AS estimated_minutes, " +
+			"COALESCE(ROUND(SUM(actual)), 0)::bigint AS actual_minutes, " +
+			"COALESCE(AVG(LEAST(estimated, actual) / GREATEST(estimated, actual)) * 100, 0)::float AS accuracy").
+		Scan(&stats.Estimates).Error; err != nil {
+		return nil, err
+	}
+	if stats.Estimates.EstimatedMinutes > 0 {
+		stats.Estimates.ActualToEstimate = float64(stats.Estimates.ActualMinutes) / float64(stats.Estimates.EstimatedMinutes)
+	}
+
 	return stats, nil
 }
 
@@ -102,8 +156,9 @@ func (r *StatsRepository) GetUpcomingTasks(userID uint, days int) ([]models.Task
 	futureDate := now.AddDate(0, 0, days)
 
 	err := r.db.Preload("Category").
-		Where("user_id = ? AND due_date BETWEEN ? AND ? AND status != ?",
-			userID, now, futureDate, models.TaskStatusCompleted).
+		Scopes(visibleTo("tasks", userID)).
+		Where("due_date BETWEEN ? AND ? AND status != ?",
+			now, futureDate, models.TaskStatusCompleted).
 		Order("due_date ASC").
 		Find(&tasks).Error
 
@@ -116,10 +171,120 @@ func (r *StatsRepository) GetOverdueTasks(userID uint) ([]models.Task, error) {
 	now := time.Now()
 
 	err := r.db.Preload("Category").
-		Where("user_id = ? AND due_date < ? AND status != ?",
-			userID, now, models.TaskStatusCompleted).
+		Scopes(visibleTo("tasks", userID)).
+		Where("due_date < ? AND status != ?",
+			now, models.TaskStatusCompleted).
 		Order("due_date ASC").
 		Find(&tasks).Error
 
 	return tasks, err
 }
+
+// GetTimeReport sums up the ended time entries that started in [from, to) on the tasks visible
+// to a user, grouped by day (UTC) or by category
+func (r *StatsRepository) GetTimeReport(userID uint, from, to time.Time, groupBy models.TimeReportGroup, workspaceID uint, mine bool) ([]models.TimeReportRow, error) {
+	query := r.db.Table("time_entries").
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id AND tasks.deleted_at IS NULL").
+		Scopes(visibleTo("tasks", userID)).
+		Where("time_entries.ended_at IS NOT NULL AND time_entries.started_at >= ? AND time_entries.started_at < ?", from, to)
+	if workspaceID > 0 {
+		query = query.Where("tasks.workspace_id = ?", workspaceID)
+	}
+	if mine {
+		query = query.Where("time_entries.user_id = ?", userID)
+	}
+
+	rows := []models.TimeReportRow{}
+	var err error
+	switch groupBy {
+	case models.TimeReportGroupCategory:
+		err = query.
+			Select("categories.id AS category_id, categories.name AS category_name, " +
+				"SUM(time_entries.duration_seconds) AS total_seconds, COUNT(*) AS entry_count").
+			Joins("LEFT JOIN categories ON categories.id = tasks.category_id").
+			Group("categories.id, categories.name").
+			Order("total_seconds DESC").
+			Scan(&rows).Error
+	default:
+		err = query.
+			Select("TO_CHAR(time_entries.started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, " +
+				"SUM(time_entries.duration_seconds) AS total_seconds, COUNT(*) AS entry_count").
+			Group("day").
+			Order("day ASC").
+			Scan(&rows).Error
+	}
+	return rows, err
+}
+
+// GetSystemStats retrieves system-wide statistics across all users
+func (r *StatsRepository) GetSystemStats() (*models.SystemStats, error) {
+	stats := &models.SystemStats{
+		TasksByStatus:   make(map[string]int64),
+		TasksByPriority: make(map[string]int64),
+	}
+
+	// 1. Users
+	if err := r.db.Model(&models.User{}).Count(&stats.TotalUsers).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&models.User{}).
+		Where("disabled_at IS NOT NULL").
+		Count(&stats.DisabledUsers).Error; err != nil {
+		return nil, err
+	}
+	stats.ActiveUsers = stats.TotalUsers - stats.DisabledUsers
+	if err := r.db.Model(&models.User{}).
+		Where("role = ?", models.UserRoleAdmin).
+		Count(&stats.AdminUsers).Error; err != nil {
+		return nil, err
+	}
+
+	// 2. Tasks
+	if err := r.db.Model(&models.Task{}).Count(&stats.TotalTasks).Error; err != nil {
+		return nil, err
+	}
+
+	type StatusCount struct {
+		Status string
+		Count  int64
+	}
+	var statusCounts []StatusCount
+	if err := r.db.Model(&models.Task{}).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&statusCounts).Error; err != nil {
+		return nil, err
+	}
+	for _, sc := range statusCounts {
+		stats.TasksByStatus[sc.Status] = sc.Count
+	}
+
+	type PriorityCount struct {
+		Priority string
+		Count    int64
+	}
+	var priorityCounts []PriorityCount
+	if err := r.db.Model(&models.Task{}).
+		Select("priority, COUNT(*) as count").
+		Group("priority").
+		Scan(&priorityCounts).Error; err != nil {
+		return nil, err
+	}
+	for _, pc := range priorityCounts {
+		stats.TasksByPriority[pc.Priority] = pc.Count
+	}
+
+	// 3. Categories
+	if err := r.db.Model(&models.Category{}).Count(&stats.TotalCategories).Error; err != nil {
+		return nil, err
+	}
+
+	// 4. Active sessions
+	if err := r.db.Model(&models.Session{}).
+		Where("revoked_at IS NULL AND expires_at > ?", time.Now()).
+		Count(&stats.ActiveSessions).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
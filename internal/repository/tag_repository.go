@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// Create creates a new tag
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// FindByID finds a tag by ID that is visible to a specific user
+func (r *TagRepository) FindByID(id uint, userID uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Scopes(visibleTo("tags", userID)).
+		Where("tags.id = ?", id).
+		First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindAllByUser finds all tags visible to a specific user with their task counts, sorted by name.
+// A non-zero workspaceID limits the result to that workspace.
+func (r *TagRepository) FindAllByUser(userID uint, workspaceID uint, page, pageSize int) ([]models.Tag, int64, error) {
+	var tags []models.Tag
+	var total int64
+
+	// Count total items
+	if err := r.visibleQuery(userID, workspaceID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Calculate offset
+	offset := (page - 1) * pageSize
+
+	// Get paginated results
+	err := r.visibleQuery(userID, workspaceID).
+		Order("LOWER(tags.name) ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&tags).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.attachTaskCounts(tags); err != nil {
+		return nil, 0, err
+	}
+	return tags, total, nil
+}
+
+// FindByNames finds the tags of one workspace, or the user's personal tags when workspaceID
+// is nil, with any of the given names regardless of case
+func (r *TagRepository) FindByNames(names []string, userID uint, workspaceID *uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	err := r.db.Scopes(inWorkspace("tags", userID, workspaceID)).
+		Where("LOWER(tags.name) IN ?", lowered).
+		Find(&tags).Error
+	return tags, err
+}
+
+// Update updates a tag
+func (r *TagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete soft deletes a tag the user may edit and removes it from all tasks
+func (r *TagRepository) Delete(id uint, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(editableBy("tags", userID)).Where("tags.id = ?", id).Delete(&models.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("tag not found")
+		}
+		return tx.Exec("DELETE FROM task_tags WHERE tag_id = ?", id).Error
+	})
+}
+
+// ExistsByName checks if a tag with the same name exists in a workspace, or among the
+// user's personal tags when workspaceID is nil. excludeID skips the tag being renamed.
+func (r *TagRepository) ExistsByName(name string, userID uint, workspaceID *uint, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Tag{}).
+		Scopes(inWorkspace("tags", userID, workspaceID)).
+		Where("LOWER(tags.name) = ? AND tags.id != ?", strings.ToLower(name), excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// attachTaskCounts sets the number of tasks labeled with each tag
+func (r *TagRepository) attachTaskCounts(tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+
+	var counts []struct {
+		TagID uint
+		Count int64
+	}
+	err := r.db.Table("task_tags").
+		Select("task_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = task_tags.task_id AND tasks.deleted_at IS NULL").
+		Where("task_tags.tag_id IN ?", ids).
+		Group("task_tags.tag_id").
+		Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+
+	byTag := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		byTag[count.TagID] = count.Count
+	}
+	for i := range tags {
+		tags[i].TaskCount = byTag[tags[i].ID]
+	}
+	return nil
+}
+
+// visibleQuery builds a query for tags visible to the user, optionally limited to one workspace
+func (r *TagRepository) visibleQuery(userID uint, workspaceID uint) *gorm.DB {
+	query := r.db.Model(&models.Tag{}).Scopes(visibleTo("tags", userID))
+	if workspaceID > 0 {
+		query = query.Where("tags.workspace_id = ?", workspaceID)
+	}
+	return query
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
@@ -22,14 +25,18 @@ func (r *TaskRepository) Create(task *models.Task) error {
 	return r.db.Create(task).Error
 }
 
-// FindByID finds a task by ID for a specific user
+// FindByID finds a task by ID that is visible to a specific user
 func (r *TaskRepository) FindByID(id uint, userID uint) (*models.Task, error) {
 	var task models.Task
 
-	// Preload User and Category relationships
+	// Preload User, Category and Assignees relationships
 	err := r.db.Preload("User").
 		Preload("Category").
-		Where("id = ? AND user_id = ?", id, userID).
+		Preload("Assignees.User").
+		Preload("Tags").
+		Preload("Series").
+		Scopes(visibleTo("tasks", userID)).
+		Where("tasks.id = ?", id).
 		First(&task).Error
 
 	if err != nil {
@@ -38,19 +45,27 @@ func (r *TaskRepository) FindByID(id uint, userID uint) (*models.Task, error) {
 		}
 		return nil, err
 	}
-	return &task, nil
+
+	tasks := []models.Task{task}
+	if err := r.attachCommentCounts(tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
 }
 
-// FindAllByUser finds all tasks for a specific user with advanced filtering
+// FindAllByUser finds all tasks visible to a specific user (personal and workspace tasks) with advanced filtering
 func (r *TaskRepository) FindAllByUser(userID uint, filter models.TaskFilter) ([]models.Task, int64, error) {
 	var tasks []models.Task
 	var total int64
 
 	// Start building query
-	query := r.db.Model(&models.Task{}).Where("user_id = ?", userID)
+	query := r.db.Model(&models.Task{}).Scopes(visibleTo("tasks", userID))
 
 	// Apply filters
-	query = r.applyFilters(query, filter)
+	query = r.applyFilters(query, userID, filter)
 
 	// Count total items (before pagination)
 	if err := query.Count(&total).Error; err != nil {
@@ -65,20 +80,33 @@ func (r *TaskRepository) FindAllByUser(userID uint, filter models.TaskFilter) ([
 	query = query.Limit(filter.PageSize).Offset(offset)
 
 	// Preload relationships and execute query
-	err := query.Preload("User").Preload("Category").Find(&tasks).Error
+	err := query.Preload("User").Preload("Category").Preload("Assignees.User").
+		Preload("Tags").
+		Preload("Series").Find(&tasks).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
+	// Load comment counts and checklist progress for the page
+	if err := r.attachCommentCounts(tasks); err != nil {
+		return nil, 0, err
+	}
+	if err := r.attachChecklistProgress(tasks); err != nil {
+		return nil, 0, err
+	}
+
 	return tasks, total, nil
 }
 
 // applyFilters applies dynamic filters to the query
-func (r *TaskRepository) applyFilters(query *gorm.DB, filter models.TaskFilter) *gorm.DB {
+func (r *TaskRepository) applyFilters(query *gorm.DB, userID uint, filter models.TaskFilter) *gorm.DB {
 	// Filter by status
 	if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
 	}
+	if filter.WorkflowStatus != "" {
+		query = query.Where("workflow_status = ?", filter.WorkflowStatus)
+	}
 
 	// Filter by priority
 	if filter.Priority != "" {
@@ -90,6 +118,61 @@ func (r *TaskRepository) applyFilters(query *gorm.DB, filter models.TaskFilter)
 		query = query.Where("category_id = ?", filter.CategoryID)
 	}
 
+	// Filter by workspace
+	if filter.WorkspaceID > 0 {
+		query = query.Where("tasks.workspace_id = ?", filter.WorkspaceID)
+	}
+
+	// Filter by assignee (validated by the service)
+	switch filter.Assignee {
+	case "":
+	case "me":
+		query = query.Scopes(assignedTo(userID))
+	case "none":
+		query = query.Where("NOT EXISTS (SELECT 1 FROM task_assignees WHERE task_assignees.task_id = tasks.id)")
+	default:
+		if assigneeID, err := strconv.ParseUint(filter.Assignee, 10, 32); err == nil {
+			query = query.Scopes(assignedTo(uint(assigneeID)))
+		}
+	}
+
+	// Filter by open blockers
+	if filter.Blocked != nil {
+		openBlockers := "EXISTS (SELECT 1 FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id " +
+			"WHERE task_dependencies.task_id = tasks.id AND blockers.status <> ? AND blockers.deleted_at IS NULL)"
+		if *filter.Blocked {
+			query = query.Where(openBlockers, models.TaskStatusCompleted)
+		} else {
+			query = query.Where("NOT "+openBlockers, models.TaskStatusCompleted)
+		}
+	}
+
+	// Filter by tags, matching any or all of the names
+	if names := filter.TagNames(); len(names) > 0 {
+		taggedWith := "FROM task_tags JOIN tags ON tags.id = task_tags.tag_id " +
+			"WHERE task_tags.task_id = tasks.id AND tags.deleted_at IS NULL AND LOWER(tags.name) IN ?"
+		if filter.TagMatch == string(models.TagMatchAll) {
+			query = query.Where("(SELECT COUNT(DISTINCT LOWER(tags.name)) "+taggedWith+") = ?", names, len(names))
+		} else {
+			query = query.Where("EXISTS (SELECT 1 "+taggedWith+")", names)
+		}
+	}
+
+	// Filter by unchecked checklist items
+	if filter.HasOpenChecklist != nil {
+		openItems := "EXISTS (SELECT 1 FROM checklist_items WHERE checklist_items.task_id = tasks.id AND checklist_items.done = ?)"
+		if *filter.HasOpenChecklist {
+			query = query.Where(openItems, false)
+		} else {
+			query = query.Where("NOT "+openItems, false)
+		}
+	}
+
+	// Filter by recurring series
+	if filter.SeriesID > 0 {
+		query = query.Where("tasks.series_id = ?", filter.SeriesID)
+	}
+
 	// Search in title and description
 	if filter.Search != "" {
 		searchPattern := "%" + filter.Search + "%"
@@ -99,6 +182,113 @@ func (r *TaskRepository) applyFilters(query *gorm.DB, filter models.TaskFilter)
 	return query
 }
 
+// attachCommentCounts fills in the comment count of each task with a single query
+func (r *TaskRepository) attachCommentCounts(tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	type CommentCount struct {
+		TaskID uint
+		Count  int64
+	}
+	var counts []CommentCount
+	if err := r.db.Model(&models.Comment{}).
+		Select("task_id, COUNT(*) as count").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]int64, len(counts))
+	for _, cc := range counts {
+		byTask[cc.TaskID] = cc.Count
+	}
+	for i := range tasks {
+		tasks[i].CommentCount = byTask[tasks[i].ID]
+	}
+	return nil
+}
+
+// attachChecklistProgress fills in the checklist progress of each task that has a checklist
+func (r *TaskRepository) attachChecklistProgress(tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	type ChecklistCount struct {
+		TaskID uint
+		Total  int64
+		Done   int64
+	}
+	var counts []ChecklistCount
+	if err := r.db.Model(&models.ChecklistItem{}).
+		Select("task_id, COUNT(*) as total, SUM(CASE WHEN done THEN 1 ELSE 0 END) as done").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]ChecklistCount, len(counts))
+	for _, cc := range counts {
+		byTask[cc.TaskID] = cc
+	}
+	for i := range tasks {
+		if cc, ok := byTask[tasks[i].ID]; ok {
+			tasks[i].Checklist = &models.ChecklistProgress{Done: cc.Done, Total: cc.Total}
+		}
+	}
+	return nil
+}
+
+// AttachProgress fills in how many direct subtasks each task has and how many are completed
+func (r *TaskRepository) AttachProgress(tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+
+	type ProgressCount struct {
+		ParentID  uint
+		Total     int64
+		Completed int64
+	}
+	var counts []ProgressCount
+	if err := r.db.Model(&models.Task{}).
+		Select("parent_id, COUNT(*) as total, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) as completed", models.TaskStatusCompleted).
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	byParent := make(map[uint]ProgressCount, len(counts))
+	for _, pc := range counts {
+		byParent[pc.ParentID] = pc
+	}
+	for i := range tasks {
+		pc := byParent[tasks[i].ID]
+		tasks[i].Progress = &models.SubtaskProgress{Completed: pc.Completed, Total: pc.Total}
+	}
+	return nil
+}
+
 // applySorting applies dynamic sorting to the query
 func (r *TaskRepository) applySorting(query *gorm.DB, filter models.TaskFilter) *gorm.DB {
 	// Build ORDER BY clause
@@ -106,16 +296,28 @@ func (r *TaskRepository) applySorting(query *gorm.DB, filter models.TaskFilter)
 	return query.Order(orderClause)
 }
 
-// Update updates a task
+// FindAllByUserWithDeleted finds every task of a user including soft deleted ones
+func (r *TaskRepository) FindAllByUserWithDeleted(userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Unscoped().
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// Update updates a task without touching its preloaded relationships
 func (r *TaskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+	return r.db.Omit(clause.Associations).Save(task).Error
 }
 
-// UpdateStatus updates only the status of a task
-func (r *TaskRepository) UpdateStatus(id uint, userID uint, status models.TaskStatus) error {
+// UpdateStatus updates only the workflow status of a task the user may edit and the status
+// of its category
+func (r *TaskRepository) UpdateStatus(id uint, userID uint, status models.TaskStatus, workflowStatus string) error {
 	result := r.db.Model(&models.Task{}).
-		Where("id = ? AND user_id = ?", id, userID).
-		Update("status", status)
+		Scopes(editableBy("tasks", userID)).
+		Where("tasks.id = ?", id).
+		Updates(map[string]interface{}{"status": status, "workflow_status": workflowStatus})
 
 	if result.Error != nil {
 		return result.Error
@@ -126,9 +328,9 @@ func (r *TaskRepository) UpdateStatus(id uint, userID uint, status models.TaskSt
 	return nil
 }
 
-// Delete soft deletes a task
+// Delete soft deletes a task the user may edit
 func (r *TaskRepository) Delete(id uint, userID uint) error {
-	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Task{})
+	result := r.db.Scopes(editableBy("tasks", userID)).Where("tasks.id = ?", id).Delete(&models.Task{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -138,30 +340,122 @@ func (r *TaskRepository) Delete(id uint, userID uint) error {
 	return nil
 }
 
-// ExistsByID checks if a task exists for a specific user
+// FindSubtasks finds the direct subtasks of a task that are visible to a specific user, oldest first
+func (r *TaskRepository) FindSubtasks(parentID uint, userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("User").
+		Preload("Category").
+		Preload("Assignees.User").
+		Preload("Tags").
+		Preload("Series").
+		Scopes(visibleTo("tasks", userID)).
+		Where("tasks.parent_id = ?", parentID).
+		Order("tasks.created_at ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachCommentCounts(tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FindParentID returns the parent of a task, nil for a top-level task
+func (r *TaskRepository) FindParentID(id uint) (*uint, error) {
+	var task models.Task
+	err := r.db.Select("id", "parent_id").Where("id = ?", id).First(&task).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+	return task.ParentID, nil
+}
+
+// FindChildren finds the direct subtasks of the given tasks. Subtasks always share the
+// workspace of their parent, so callers check access on the parents.
+func (r *TaskRepository) FindChildren(parentIDs []uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where("parent_id IN ?", parentIDs).Order("id ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+// FindDescendants finds every subtask below the given tasks, level by level: a subtask
+// always comes after its parent
+func (r *TaskRepository) FindDescendants(ids []uint) ([]models.Task, error) {
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	var descendants []models.Task
+	level := ids
+	for len(level) > 0 {
+		children, err := r.FindChildren(level)
+		if err != nil {
+			return nil, err
+		}
+
+		level = nil
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			descendants = append(descendants, child)
+			level = append(level, child.ID)
+		}
+	}
+	return descendants, nil
+}
+
+// SetParent moves tasks below a parent, or to the top level when parentID is nil
+func (r *TaskRepository) SetParent(ids []uint, parentID *uint) error {
+	var value interface{}
+	if parentID != nil {
+		value = *parentID
+	}
+	return r.db.Model(&models.Task{}).Where("id IN ?", ids).Update("parent_id", value).Error
+}
+
+// DeleteByIDs soft deletes tasks; callers check that the user may edit them
+func (r *TaskRepository) DeleteByIDs(ids []uint) error {
+	return r.db.Where("id IN ?", ids).Delete(&models.Task{}).Error
+}
+
+// ExistsByID checks if a task exists and is visible to a specific user
 func (r *TaskRepository) ExistsByID(id uint, userID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Task{}).
-		Where("id = ? AND user_id = ?", id, userID).
+		Scopes(visibleTo("tasks", userID)).
+		Where("tasks.id = ?", id).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// CategoryExistsForUser checks if a category belongs to the user
+// CategoryExistsForUser checks if a category is visible to the user
 func (r *TaskRepository) CategoryExistsForUser(categoryID uint, userID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Category{}).
-		Where("id = ? AND user_id = ?", categoryID, userID).
+		Scopes(visibleTo("categories", userID)).
+		Where("categories.id = ?", categoryID).
 		Count(&count).Error
 	return count > 0, err
 }
 
 // BulkUpdateStatus updates status for multiple tasks
-func (r *TaskRepository) BulkUpdateStatus(taskIDs []uint, userID uint, status models.TaskStatus) (int64, error) {
-	// Update only tasks that belong to the user
+func (r *TaskRepository) BulkUpdateStatus(taskIDs []uint, userID uint, status models.TaskStatus, workflowStatus string) (int64, error) {
+	// Update only tasks the user may edit
 	result := r.db.Model(&models.Task{}).
-		Where("id IN ? AND user_id = ?", taskIDs, userID).
-		Update("status", status)
+		Scopes(editableBy("tasks", userID)).
+		Where("tasks.id IN ?", taskIDs).
+		Updates(map[string]interface{}{"status": status, "workflow_status": workflowStatus})
 
 	if result.Error != nil {
 		return 0, result.Error
@@ -170,12 +464,180 @@ func (r *TaskRepository) BulkUpdateStatus(taskIDs []uint, userID uint, status mo
 	return result.RowsAffected, nil
 }
 
-// FindByIDs finds multiple tasks by IDs for a specific user
+// FindEditableByIDs finds the tasks among the IDs that the user may edit
+func (r *TaskRepository) FindEditableByIDs(taskIDs []uint, userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Scopes(editableBy("tasks", userID)).
+		Where("tasks.id IN ?", taskIDs).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindByIDs finds multiple tasks by IDs that are visible to a specific user
 func (r *TaskRepository) FindByIDs(taskIDs []uint, userID uint) ([]models.Task, error) {
 	var tasks []models.Task
 	err := r.db.Preload("User").
 		Preload("Category").
-		Where("id IN ? AND user_id = ?", taskIDs, userID).
+		Preload("Assignees.User").
+		Preload("Tags").
+		Preload("Series").
+		Scopes(visibleTo("tasks", userID)).
+		Where("tasks.id IN ?", taskIDs).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// AddAssignees assigns users to a task; users that are already assigned are skipped
+func (r *TaskRepository) AddAssignees(taskID uint, userIDs []uint, assignedByID uint) error {
+	assignees := make([]models.TaskAssignee, 0, len(userIDs))
+	for _, userID := range userIDs {
+		assignees = append(assignees, models.TaskAssignee{
+			TaskID:       taskID,
+			UserID:       userID,
+			AssignedByID: assignedByID,
+		})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignees).Error
+}
+
+// RemoveAssignee unassigns a user from a task
+func (r *TaskRepository) RemoveAssignee(taskID uint, userID uint) error {
+	result := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&models.TaskAssignee{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("assignee not found")
+	}
+	return nil
+}
+
+// ReplaceTags sets the tags of a task, removing the ones not in the list
+func (r *TaskRepository) ReplaceTags(task *models.Task, tags []models.Tag) error {
+	return r.db.Model(task).Omit("Tags.*").Association("Tags").Replace(tags)
+}
+
+// AddDependency marks a task as blocked by another task. It reports false when the
+// dependency already existed.
+func (r *TaskRepository) AddDependency(taskID uint, blockerID uint, createdByID uint) (bool, error) {
+	dependency := models.TaskDependency{
+		TaskID:      taskID,
+		BlockerID:   blockerID,
+		CreatedByID: createdByID,
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&dependency)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveDependency removes a dependency between two tasks
+func (r *TaskRepository) RemoveDependency(taskID uint, blockerID uint) error {
+	result := r.db.Where("task_id = ? AND blocker_id = ?", taskID, blockerID).Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("dependency not found")
+	}
+	return nil
+}
+
+// FindBlockerIDs finds the tasks that directly block any of the given tasks
+func (r *TaskRepository) FindBlockerIDs(taskIDs []uint) ([]uint, error) {
+	var blockerIDs []uint
+	err := r.db.Model(&models.TaskDependency{}).
+		Where("task_id IN ?", taskIDs).
+		Distinct().
+		Pluck("blocker_id", &blockerIDs).Error
+	return blockerIDs, err
+}
+
+// FindOpenBlockers finds the dependencies of the given tasks whose blocker is not completed
+func (r *TaskRepository) FindOpenBlockers(taskIDs []uint) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	err := r.db.Joins("JOIN tasks ON tasks.id = task_dependencies.blocker_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.task_id IN ?", taskIDs).
+		Where("tasks.status <> ?", models.TaskStatusCompleted).
+		Find(&dependencies).Error
+	return dependencies, err
+}
+
+// FindTimeSpent sums up the time tracked on a task, counting running timers up to now
+func (r *TaskRepository) FindTimeSpent(taskID uint) (*models.TimeSpent, error) {
+	var spent models.TimeSpent
+	err := r.db.Model(&models.TimeEntry{}).
+		Select("COUNT(*) AS entry_count, "+
+			"COUNT(*) FILTER (WHERE ended_at IS NULL) AS running, "+
+			"COALESCE(SUM(CASE WHEN ended_at IS NULL THEN FLOOR(EXTRACT(EPOCH FROM (? - started_at))) ELSE duration_seconds END), 0)::bigint AS total_seconds", time.Now()).
+		Where("task_id = ?", taskID).
+		Scan(&spent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &spent, nil
+}
+
+// FindDependencies finds the tasks that block a task and the tasks it blocks. Deleted tasks are left out.
+func (r *TaskRepository) FindDependencies(taskID uint) (*models.TaskDependencies, error) {
+	dependencies := &models.TaskDependencies{
+		BlockedBy: []models.TaskReference{},
+		Blocking:  []models.TaskReference{},
+	}
+
+	if err := r.db.Model(&models.Task{}).
+		Select("tasks.id, tasks.title, tasks.status").
+		Joins("JOIN task_dependencies ON task_dependencies.blocker_id = tasks.id").
+		Where("task_dependencies.task_id = ?", taskID).
+		Order("tasks.id ASC").
+		Scan(&dependencies.BlockedBy).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(&models.Task{}).
+		Select("tasks.id, tasks.title, tasks.status").
+		Joins("JOIN task_dependencies ON task_dependencies.task_id = tasks.id").
+		Where("task_dependencies.blocker_id = ?", taskID).
+		Order("tasks.id ASC").
+		Scan(&dependencies.Blocking).Error; err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+// FindSeriesByID finds a recurring task series that is visible to a specific user
+func (r *TaskRepository) FindSeriesByID(id uint, userID uint) (*models.TaskSeries, error) {
+	var series models.TaskSeries
+	err := r.db.Scopes(visibleTo("task_series", userID)).
+		Where("task_series.id = ?", id).
+		First(&series).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// UpdateSeries updates a recurring task series
+func (r *TaskRepository) UpdateSeries(series *models.TaskSeries) error {
+	return r.db.Save(series).Error
+}
+
+// FindOpenBySeries finds the occurrences of a series that are not completed
+func (r *TaskRepository) FindOpenBySeries(seriesID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where("series_id = ? AND status <> ?", seriesID, models.TaskStatusCompleted).
+		Order("due_date ASC").
 		Find(&tasks).Error
 	return tasks, err
 }
+
+// HasLaterOccurrence checks if a series already has an occurrence due after the given time
+func (r *TaskRepository) HasLaterOccurrence(seriesID uint, dueDate time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).
+		Where("series_id = ? AND due_date > ?", seriesID, dueDate).
+		Count(&count).Error
+	return count > 0, err
+}
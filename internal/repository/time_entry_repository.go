@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type TimeEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeEntryRepository creates a new time entry repository
+func NewTimeEntryRepository(db *gorm.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+// Create creates a new time entry
+func (r *TimeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// FindByID finds a time entry of a task with its user
+func (r *TimeEntryRepository) FindByID(id uint, taskID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.Preload("User").
+		Where("id = ? AND task_id = ?", id, taskID).
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("time entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindAllByTask finds the time entries of a task, newest first
+func (r *TimeEntryRepository) FindAllByTask(taskID uint) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.db.Preload("User").
+		Where("task_id = ?", taskID).
+		Order("started_at DESC, id DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindRunningByUser finds the running timer of a user, nil when none is running
+func (r *TimeEntryRepository) FindRunningByUser(userID uint) (*models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.db.Preload("User").
+		Where("user_id = ? AND ended_at IS NULL", userID).
+		Limit(1).
+		Find(&entries).Error
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// HasOverlap checks whether a period overlaps another entry of the user. A nil end means the
+// period is still running; running timers overlap everything after their start.
+func (r *TimeEntryRepository) HasOverlap(userID uint, start time.Time, end *time.Time, excludeID uint) (bool, error) {
+	query := r.db.Model(&models.TimeEntry{}).
+		Where("user_id = ? AND id <> ?", userID, excludeID).
+		Where("ended_at IS NULL OR ended_at > ?", start)
+	if end != nil {
+		query = query.Where("started_at < ?", *end)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update saves the period and note of a time entry
+func (r *TimeEntryRepository) Update(entry *models.TimeEntry) error {
+	return r.db.Model(entry).
+		Select("started_at", "ended_at", "duration_seconds", "note").
+		Updates(entry).Error
+}
+
+// Delete removes a time entry
+func (r *TimeEntryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TimeEntry{}, id).Error
+}
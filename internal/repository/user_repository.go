@@ -2,6 +2,8 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"gorm.io/gorm"
@@ -60,6 +62,16 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByUsernames finds the users with the given usernames; unknown usernames are skipped
+func (r *UserRepository) FindByUsernames(usernames []string) ([]models.User, error) {
+	var users []models.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
+
 // ExistsByUsername checks if username already exists
 func (r *UserRepository) ExistsByUsername(username string) (bool, error) {
 	var count int64
@@ -74,12 +86,230 @@ func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
 	return count > 0, err
 }
 
-// Update updates a user
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+// UpdatePassword saves a new password hash
+func (r *UserRepository) UpdatePassword(id uint, passwordHash string) error {
+	return r.updateColumns(id, map[string]interface{}{"password": passwordHash})
+}
+
+// MarkEmailVerified records that the user verified an email address. It reports false when
+// the email of the user has changed in the meantime.
+func (r *UserRepository) MarkEmailVerified(id uint, email string, verifiedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND email = ?", id, email).
+		Update("email_verified_at", verifiedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateProfile saves the username, email and full name of a user. A changed email is no
+// longer verified.
+func (r *UserRepository) UpdateProfile(user *models.User, emailChanged bool) error {
+	columns := map[string]interface{}{
+		"username":  user.Username,
+		"email":     user.Email,
+		"full_name": user.FullName,
+	}
+	if emailChanged {
+		columns["email_verified_at"] = nil
+	}
+	return r.updateColumns(user.ID, columns)
+}
+
+// UpdateDisabledAt disables a user at the given time, or enables them when disabledAt is nil
+func (r *UserRepository) UpdateDisabledAt(id uint, disabledAt *time.Time) error {
+	return r.updateColumns(id, map[string]interface{}{"disabled_at": disabledAt})
+}
+
+// UpdateRole saves the role of a user
+func (r *UserRepository) UpdateRole(id uint, role models.UserRole) error {
+	return r.updateColumns(id, map[string]interface{}{"role": role})
+}
+
+// UpdateTwoFactor saves the two-factor columns of a user without touching the rest of the row
+func (r *UserRepository) UpdateTwoFactor(id uint, secret string, lastCounter int64, enabledAt *time.Time) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_secret":           secret,
+			"totp_last_counter":     lastCounter,
+			"two_factor_enabled_at": enabledAt,
+		}).Error
+}
+
+// ConsumeTOTPCounter records the time step of a used TOTP code. It reports false when the
+// same or a later step was already used, so each code is accepted at most once.
+func (r *UserRepository) ConsumeTOTPCounter(id uint, counter int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_counter < ?", id, counter).
+		UpdateColumn("totp_last_counter", counter)
+	return result.RowsAffected == 1, result.Error
 }
 
 // Delete soft deletes a user
 func (r *UserRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
+
+// DeleteAccount soft deletes a user together with their personal tasks and categories and
+// the workspaces they own, removes their memberships and revokes everything that could
+// still authenticate them. The username and email are released
+// so they can be registered again.
+func (r *UserRepository) DeleteAccount(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Workspaces the user owns have no other members left (see OwnsSharedWorkspace)
+		ownedWorkspaces := tx.Model(&models.Workspace{}).Select("id").Where("owner_id = ?", id)
+		if err := tx.Where("workspace_id IN (?)", ownedWorkspaces).Delete(&models.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id IN (?)", ownedWorkspaces).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id IN (?)", ownedWorkspaces).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := deleteWorkflows(tx, tx.Model(&models.Workflow{}).Select("id").Where("workspace_id IN (?)", ownedWorkspaces)); err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id IN (?)", ownedWorkspaces).Delete(&models.WorkspaceInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_id = ?", id).Delete(&models.Workspace{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+
+		// Tasks, categories and tags in other workspaces stay with the team
+		if err := tx.Where("user_id = ? AND workspace_id IS NULL", id).Delete(&models.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND workspace_id IS NULL", id).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND workspace_id IS NULL", id).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := deleteWorkflows(tx, tx.Model(&models.Workflow{}).Select("id").Where("user_id = ? AND workspace_id IS NULL", id)); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND used_at IS NULL", id).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		// Expire finished data exports so their archives are removed by the cleanup job
+		if err := tx.Model(&models.DataExport{}).
+			Where("user_id = ? AND status = ?", id, models.DataExportStatusCompleted).
+			Update("expires_at", now).Error; err != nil {
+			return err
+		}
+
+		// Unique columns keep their values after a soft delete, so free them up
+		placeholder := fmt.Sprintf("deleted_%d_%d", id, now.Unix())
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"username":    placeholder,
+				"email":       placeholder + "@deleted.invalid",
+				"totp_secret": "",
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.User{}, id).Error
+	})
+}
+
+// OwnsSharedWorkspace checks if the user owns a workspace that has other members
+func (r *UserRepository) OwnsSharedWorkspace(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Workspace{}).
+		Where("owner_id = ?", id).
+		Where("EXISTS (SELECT 1 FROM workspace_members WHERE workspace_members.workspace_id = workspaces.id AND workspace_members.user_id != ?)", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindAll finds users with filtering and pagination
+func (r *UserRepository) FindAll(filter models.AdminUserFilter) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+
+	// Search in username, email and full name
+	if filter.Search != "" {
+		searchPattern := "%" + filter.Search + "%"
+		query = query.Where("username ILIKE ? OR email ILIKE ? OR full_name ILIKE ?", searchPattern, searchPattern, searchPattern)
+	}
+
+	// Filter by role
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	// Filter by status
+	switch filter.Status {
+	case "active":
+		query = query.Where("disabled_at IS NULL")
+	case "disabled":
+		query = query.Where("disabled_at IS NOT NULL")
+	}
+
+	// Count total items
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Calculate offset
+	offset := (filter.Page - 1) * filter.PageSize
+
+	err := query.Order("created_at DESC").
+		Limit(filter.PageSize).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// updateColumns saves only the given columns of a user, so concurrent changes to other
+// columns (2FA state, role, disabled flag, ...) are not overwritten with stale values
+func (r *UserRepository) updateColumns(id uint, columns map[string]interface{}) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// PromoteToAdmin gives the admin role to the users with the given usernames
+func (r *UserRepository) PromoteToAdmin(usernames []string) (int64, error) {
+	result := r.db.Model(&models.User{}).
+		Where("username IN ? AND role != ?", usernames, models.UserRoleAdmin).
+		Update("role", models.UserRoleAdmin)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *gorm.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Create creates a new user token
+func (r *UserTokenRepository) Create(token *models.UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindValid finds an unused, unexpired token by hash and purpose
+func (r *UserTokenRepository) FindValid(tokenHash string, purpose models.TokenPurpose) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed marks a token as used.
+// It returns false if the token had already been used.
+func (r *UserTokenRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateAll marks every unused token of a user with the given purpose as used
+func (r *UserTokenRepository) InvalidateAll(userID uint, purpose models.TokenPurpose) error {
+	return r.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+// IncrementAttempts records a failed attempt and returns the new attempt count
+func (r *UserTokenRepository) IncrementAttempts(id uint) (int, error) {
+	if err := r.db.Model(&models.UserToken{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+		return 0, err
+	}
+
+	var token models.UserToken
+	if err := r.db.Select("attempts").First(&token, id).Error; err != nil {
+		return 0, err
+	}
+	return token.Attempts, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type WorkflowRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkflowRepository creates a new workflow repository
+func NewWorkflowRepository(db *gorm.DB) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+// Create creates a workflow with its statuses and transitions and moves the tasks of its
+// scope to the new statuses
+func (r *WorkflowRepository) Create(workflow *models.Workflow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workflow).Error; err != nil {
+			return err
+		}
+		return remapTasks(tx, workflow)
+	})
+}
+
+// FindByID finds a workflow by ID that is visible to a specific user
+func (r *WorkflowRepository) FindByID(id uint, userID uint) (*models.Workflow, error) {
+	var workflow models.Workflow
+	err := r.withDefinition().
+		Scopes(visibleTo("workflows", userID)).
+		Where("workflows.id = ?", id).
+		First(&workflow).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("workflow not found")
+		}
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// FindAllByUser finds all workflows visible to a specific user, the personal one first.
+// A non-zero workspaceID limits the result to that workspace.
+func (r *WorkflowRepository) FindAllByUser(userID uint, workspaceID uint) ([]models.Workflow, error) {
+	query := r.withDefinition().Scopes(visibleTo("workflows", userID))
+	if workspaceID > 0 {
+		query = query.Where("workflows.workspace_id = ?", workspaceID)
+	}
+
+	var workflows []models.Workflow
+	err := query.Order("workflows.workspace_id NULLS FIRST, workflows.id").Find(&workflows).Error
+	return workflows, err
+}
+
+// FindByScope finds the workflow of a workspace, or the personal workflow of the user when
+// workspaceID is nil. It returns nil when the scope has no workflow of its own.
+func (r *WorkflowRepository) FindByScope(workspaceID *uint, userID uint) (*models.Workflow, error) {
+	var workflows []models.Workflow
+	err := r.withDefinition().
+		Scopes(inWorkspace("workflows", userID, workspaceID)).
+		Limit(1).
+		Find(&workflows).Error
+	if err != nil || len(workflows) == 0 {
+		return nil, err
+	}
+	return &workflows[0], nil
+}
+
+// FindStatusesInUse returns the keys among the given ones that tasks of a scope are in
+func (r *WorkflowRepository) FindStatusesInUse(workspaceID *uint, userID uint, keys []string) ([]string, error) {
+	var inUse []string
+	if len(keys) == 0 {
+		return inUse, nil
+	}
+	err := r.db.Model(&models.Task{}).
+		Scopes(inWorkspace("tasks", userID, workspaceID)).
+		Where("workflow_status IN ?", keys).
+		Distinct().
+		Order("workflow_status").
+		Pluck("workflow_status", &inUse).Error
+	return inUse, err
+}
+
+// Update saves the name of a workflow, replaces its statuses and transitions and moves the
+// tasks of its scope to the new statuses
+func (r *WorkflowRepository) Update(workflow *models.Workflow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Workflow{}).Where("id = ?", workflow.ID).Update("name", workflow.Name).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.WorkflowStatus{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.WorkflowTransition{}).Error; err != nil {
+			return err
+		}
+		for i := range workflow.Statuses {
+			workflow.Statuses[i].ID = 0
+			workflow.Statuses[i].WorkflowID = workflow.ID
+		}
+		for i := range workflow.Transitions {
+			workflow.Transitions[i].ID = 0
+			workflow.Transitions[i].WorkflowID = workflow.ID
+		}
+		if err := tx.Create(&workflow.Statuses).Error; err != nil {
+			return err
+		}
+		if len(workflow.Transitions) > 0 {
+			if err := tx.Create(&workflow.Transitions).Error; err != nil {
+				return err
+			}
+		}
+
+		return remapTasks(tx, workflow)
+	})
+}
+
+// Delete removes a workflow; the tasks of its scope go back to the default statuses
+func (r *WorkflowRepository) Delete(workflow *models.Workflow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Task{}).
+			Scopes(inWorkspace("tasks", workflow.UserID, workflow.WorkspaceID)).
+			UpdateColumn("workflow_status", gorm.Expr("status")).Error; err != nil {
+			return err
+		}
+		return deleteWorkflows(tx, []uint{workflow.ID})
+	})
+}
+
+// withDefinition loads workflows with their statuses in order and their transitions
+func (r *WorkflowRepository) withDefinition() *gorm.DB {
+	return r.db.
+		Preload("Statuses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		})
+}
+
+// remapTasks moves the tasks of a workflow's scope, including deleted ones, to its statuses.
+// Deleted tasks take the category of their status, and tasks whose status no longer exists
+// move to the first status of their category. Changing the category of tasks that are not
+// deleted would skip the checks of a status change, so it fails with "status in use".
+func remapTasks(tx *gorm.DB, workflow *models.Workflow) error {
+	tasks := func() *gorm.DB {
+		return tx.Unscoped().Model(&models.Task{}).
+			Scopes(inWorkspace("tasks", workflow.UserID, workflow.WorkspaceID))
+	}
+
+	conditions := make([]string, len(workflow.Statuses))
+	args := make([]interface{}, 0, 2*len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		conditions[i] = "(workflow_status = ? AND status <> ?)"
+		args = append(args, status.Key, status.Category.TaskStatus())
+	}
+	var recategorized int64
+	if err := tx.Model(&models.Task{}).
+		Scopes(inWorkspace("tasks", workflow.UserID, workflow.WorkspaceID)).
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Count(&recategorized).Error; err != nil {
+		return err
+	}
+	if recategorized > 0 {
+		return errors.New("status in use")
+	}
+
+	keys := make([]string, len(workflow.Statuses))
+	for i, status := range workflow.Statuses {
+		keys[i] = status.Key
+		if err := tasks().
+			Where("workflow_status = ? AND status <> ?", status.Key, status.Category.TaskStatus()).
+			UpdateColumn("status", status.Category.TaskStatus()).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, category := range models.StatusCategories {
+		first := workflow.FirstStatus(category)
+		if first == nil {
+			continue
+		}
+		if err := tasks().
+			Where("status = ? AND workflow_status NOT IN ?", category.TaskStatus(), keys).
+			UpdateColumn("workflow_status", first.Key).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteWorkflows removes workflows with their statuses and transitions. ids is a list of IDs
+// or a subquery selecting them.
+func deleteWorkflows(tx *gorm.DB, ids interface{}) error {
+	if err := tx.Where("workflow_id IN (?)", ids).Delete(&models.WorkflowStatus{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("workflow_id IN (?)", ids).Delete(&models.WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN (?)", ids).Delete(&models.Workflow{}).Error
+}
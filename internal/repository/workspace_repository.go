@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"gorm.io/gorm"
+)
+
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// Create creates a workspace and makes its owner a member
+func (r *WorkspaceRepository) Create(workspace *models.Workspace) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        models.WorkspaceRoleOwner,
+		}).Error
+	})
+}
+
+// FindByID finds a workspace the user is a member of, including the user's role
+func (r *WorkspaceRepository) FindByID(id uint, userID uint) (*models.Workspace, error) {
+	role, err := r.FindRole(id, userID)
+	if err != nil {
+		return nil, errors.New("workspace not found")
+	}
+
+	var workspace models.Workspace
+	if err := r.db.First(&workspace, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("workspace not found")
+		}
+		return nil, err
+	}
+
+	workspace.Role = role
+	return &workspace, nil
+}
+
+// FindAllByUser finds all workspaces a user is a member of, with the user's role and member counts
+func (r *WorkspaceRepository) FindAllByUser(userID uint) ([]models.Workspace, error) {
+	type workspaceRow struct {
+		models.Workspace
+		MemberRole  models.WorkspaceRole
+		MemberTotal int64
+	}
+
+	var rows []workspaceRow
+	err := r.db.Model(&models.Workspace{}).
+		Select("workspaces.*, workspace_members.role AS member_role, "+
+			"(SELECT COUNT(*) FROM workspace_members m WHERE m.workspace_id = workspaces.id) AS member_total").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = ?", userID).
+		Order("workspaces.created_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]models.Workspace, len(rows))
+	for i, row := range rows {
+		workspaces[i] = row.Workspace
+		workspaces[i].Role = row.MemberRole
+		workspaces[i].MemberCount = row.MemberTotal
+	}
+	return workspaces, nil
+}
+
+// Update updates a workspace
+func (r *WorkspaceRepository) Update(workspace *models.Workspace) error {
+	return r.db.Save(workspace).Error
+}
+
+// Delete soft deletes a workspace with its tasks and categories and removes its workflow, members and invitations
+func (r *WorkspaceRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.Task{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := deleteWorkflows(tx, tx.Model(&models.Workflow{}).Select("id").Where("workspace_id = ?", id)); err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Workspace{}, id).Error
+	})
+}
+
+// FindRole returns the role of a user in a workspace
+func (r *WorkspaceRepository) FindRole(workspaceID uint, userID uint) (models.WorkspaceRole, error) {
+	member, err := r.FindMember(workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// FindMember finds the membership of a user in a workspace
+func (r *WorkspaceRepository) FindMember(workspaceID uint, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := r.db.Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("workspace member not found")
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers finds all members of a workspace
+func (r *WorkspaceRepository) FindMembers(workspaceID uint) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	err := r.db.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// IsMember checks if a user is a member of a workspace
+func (r *WorkspaceRepository) IsMember(workspaceID uint, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateMemberRole changes the role of a member
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID uint, userID uint, role models.WorkspaceRole) error {
+	result := r.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("workspace member not found")
+	}
+	return nil
+}
+
+// TransferOwnership makes a member the owner; the previous owner becomes an admin
+func (r *WorkspaceRepository) TransferOwnership(workspaceID uint, fromUserID uint, toUserID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, fromUserID).
+			Update("role", models.WorkspaceRoleAdmin).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, toUserID).
+			Update("role", models.WorkspaceRoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Workspace{}).
+			Where("id = ?", workspaceID).
+			Update("owner_id", toUserID).Error
+	})
+}
+
+// RemoveMember removes a user from a workspace and from the assignees of its tasks
+func (r *WorkspaceRepository) RemoveMember(workspaceID uint, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&models.WorkspaceMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("workspace member not found")
+		}
+
+		// Former members can no longer see the workspace tasks, so unassign them
+		workspaceTasks := tx.Unscoped().Model(&models.Task{}).Select("id").Where("workspace_id = ?", workspaceID)
+		return tx.Where("user_id = ? AND task_id IN (?)", userID, workspaceTasks).Delete(&models.TaskAssignee{}).Error
+	})
+}
+
+// CreateInvitation creates a new invitation
+func (r *WorkspaceRepository) CreateInvitation(invitation *models.WorkspaceInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// FindInvitationByTokenHash finds a pending invitation by the hash of its token
+func (r *WorkspaceRepository) FindInvitationByTokenHash(tokenHash string) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	err := r.db.Scopes(pendingInvitations).
+		Where("token_hash = ?", tokenHash).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindInvitations finds the pending invitations of a workspace
+func (r *WorkspaceRepository) FindInvitations(workspaceID uint) ([]models.WorkspaceInvitation, error) {
+	var invitations []models.WorkspaceInvitation
+	err := r.db.Scopes(pendingInvitations).
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// RevokeInvitation revokes a pending invitation of a workspace
+func (r *WorkspaceRepository) RevokeInvitation(id uint, workspaceID uint) error {
+	result := r.db.Model(&models.WorkspaceInvitation{}).
+		Scopes(pendingInvitations).
+		Where("id = ? AND workspace_id = ?", id, workspaceID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+// AcceptInvitation adds the member and records the use of the invitation.
+// Email invitations are claimed atomically so they can only be used once.
+func (r *WorkspaceRepository) AcceptInvitation(invitation *models.WorkspaceInvitation, member *models.WorkspaceMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WorkspaceInvitation{}).
+			Scopes(pendingInvitations).
+			Where("id = ?", invitation.ID).
+			Updates(map[string]interface{}{
+				"accepted_at": time.Now(),
+				"use_count":   gorm.Expr("use_count + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invitation not found")
+		}
+
+		return tx.Create(member).Error
+	})
+}
+
+// pendingInvitations limits a query to invitations that can still be accepted
+func pendingInvitations(db *gorm.DB) *gorm.DB {
+	return db.Where("revoked_at IS NULL AND expires_at > ? AND (email = '' OR accepted_at IS NULL)", time.Now())
+}
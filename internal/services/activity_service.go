@@ -0,0 +1,236 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type ActivityService struct {
+	activityRepo  *repository.ActivityRepository
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewActivityService creates a new activity service
+func NewActivityService(activityRepo *repository.ActivityRepository, workspaceRepo *repository.WorkspaceRepository) *ActivityService {
+	return &ActivityService{
+		activityRepo:  activityRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// Record stores activities. Failures are logged and never fail the change that caused them.
+func (s *ActivityService) Record(activities ...models.Activity) {
+	if err := s.activityRepo.CreateBatch(activities); err != nil {
+		log.Printf("❌ Failed to record %d activities: %v", len(activities), err)
+	}
+}
+
+// RecordTask records a change of a task. before is nil for created tasks and after is nil
+// for deleted ones; the changed fields are recorded for everything else. Updates that did not
+// change anything are skipped.
+func (s *ActivityService) RecordTask(action models.ActivityAction, before, after *models.Task, userID uint, requestID string) {
+	activity := taskActivity(action, before, after, userID, requestID)
+	if before != nil && after != nil && len(activity.Changes) == 0 {
+		return
+	}
+	s.Record(activity)
+}
+
+// RecordTaskAssignees records users being assigned to or unassigned from a task
+func (s *ActivityService) RecordTaskAssignees(task *models.Task, added, removed []uint, userID uint, requestID string) {
+	activity := taskActivity(models.ActivityActionUpdated, task, nil, userID, requestID)
+	for _, id := range added {
+		activity.Changes = appendChange(activity.Changes, "assignee", nil, idValue(&id))
+	}
+	for _, id := range removed {
+		activity.Changes = appendChange(activity.Changes, "assignee", idValue(&id), nil)
+	}
+	if len(activity.Changes) > 0 {
+		s.Record(activity)
+	}
+}
+
+// RecordTaskBlockers records tasks being added as blockers of a task or removed
+func (s *ActivityService) RecordTaskBlockers(task *models.Task, added, removed []uint, userID uint, requestID string) {
+	activity := taskActivity(models.ActivityActionUpdated, task, nil, userID, requestID)
+	for _, id := range added {
+		activity.Changes = appendChange(activity.Changes, "blocked_by", nil, idValue(&id))
+	}
+	for _, id := range removed {
+		activity.Changes = appendChange(activity.Changes, "blocked_by", idValue(&id), nil)
+	}
+	if len(activity.Changes) > 0 {
+		s.Record(activity)
+	}
+}
+
+// RecordTaskTags records tags being added to or removed from a task
+func (s *ActivityService) RecordTaskTags(task *models.Task, added, removed []string, userID uint, requestID string) {
+	activity := taskActivity(models.ActivityActionUpdated, task, nil, userID, requestID)
+	for _, name := range added {
+		activity.Changes = appendChange(activity.Changes, "tag", nil, textValue(name))
+	}
+	for _, name := range removed {
+		activity.Changes = appendChange(activity.Changes, "tag", textValue(name), nil)
+	}
+	if len(activity.Changes) > 0 {
+		s.Record(activity)
+	}
+}
+
+// RecordTaskAttachments records files being attached to or removed from a task
+func (s *ActivityService) RecordTaskAttachments(task *models.Task, added, removed []string, userID uint, requestID string) {
+	activity := taskActivity(models.ActivityActionUpdated, task, nil, userID, requestID)
+	for _, name := range added {
+		activity.Changes = appendChange(activity.Changes, "attachment", nil, textValue(name))
+	}
+	for _, name := range removed {
+		activity.Changes = appendChange(activity.Changes, "attachment", textValue(name), nil)
+	}
+	if len(activity.Changes) > 0 {
+		s.Record(activity)
+	}
+}
+
+// RecordCategory records a change of a category like RecordTask
+func (s *ActivityService) RecordCategory(action models.ActivityAction, before, after *models.Category, userID uint, requestID string) {
+	activity := categoryActivity(action, before, after, userID, requestID)
+	if before != nil && after != nil && len(activity.Changes) == 0 {
+		return
+	}
+	s.Record(activity)
+}
+
+// GetHistory retrieves the activities of a task or category. Callers check that the user can see it.
+func (s *ActivityService) GetHistory(entityType models.ActivityEntityType, entityID uint, filter models.ActivityFilter) ([]models.Activity, int64, error) {
+	filter.SetDefaults()
+	return s.activityRepo.FindByEntity(entityType, entityID, filter.Page, filter.PageSize)
+}
+
+// GetFeed retrieves the activities on the user's personal tasks and categories and on those
+// of the user's workspaces
+func (s *ActivityService) GetFeed(userID uint, filter models.ActivityFilter) ([]models.Activity, int64, error) {
+	filter.SetDefaults()
+
+	if filter.WorkspaceID > 0 {
+		isMember, err := s.workspaceRepo.IsMember(filter.WorkspaceID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !isMember {
+			return nil, 0, utils.ErrWorkspaceNotFound
+		}
+	}
+
+	return s.activityRepo.FindFeed(userID, filter)
+}
+
+// taskActivity builds the activity for a change of a task
+func taskActivity(action models.ActivityAction, before, after *models.Task, userID uint, requestID string) models.Activity {
+	current := after
+	if current == nil {
+		current = before
+	}
+	if before == nil {
+		before = &models.Task{}
+	}
+
+	activity := models.Activity{
+		EntityType:  models.ActivityEntityTask,
+		EntityID:    current.ID,
+		EntityName:  current.Title,
+		WorkspaceID: current.WorkspaceID,
+		Action:      action,
+		UserID:      userID,
+		RequestID:   requestID,
+	}
+	if after != nil {
+		activity.Changes = appendChange(activity.Changes, "title", textValue(before.Title), textValue(after.Title))
+		activity.Changes = appendChange(activity.Changes, "description", textValue(before.Description), textValue(after.Description))
+		activity.Changes = appendChange(activity.Changes, "status", textValue(string(before.Status)), textValue(string(after.Status)))
+		activity.Changes = appendChange(activity.Changes, "workflow_status", textValue(before.WorkflowStatus), textValue(after.WorkflowStatus))
+		activity.Changes = appendChange(activity.Changes, "priority", textValue(string(before.Priority)), textValue(string(after.Priority)))
+		activity.Changes = appendChange(activity.Changes, "due_date", timeValue(before.DueDate), timeValue(after.DueDate))
+		activity.Changes = appendChange(activity.Changes, "category_id", idValue(before.CategoryID), idValue(after.CategoryID))
+		activity.Changes = appendChange(activity.Changes, "parent_id", idValue(before.ParentID), idValue(after.ParentID))
+		activity.Changes = appendChange(activity.Changes, "estimate_minutes", intValue(before.EstimateMinutes), intValue(after.EstimateMinutes))
+		activity.Changes = appendChange(activity.Changes, "remaining_minutes", intValue(before.RemainingMinutes), intValue(after.RemainingMinutes))
+		activity.Changes = appendChange(activity.Changes, "story_points", intValue(before.StoryPoints), intValue(after.StoryPoints))
+	}
+	return activity
+}
+
+// categoryActivity builds the activity for a change of a category
+func categoryActivity(action models.ActivityAction, before, after *models.Category, userID uint, requestID string) models.Activity {
+	current := after
+	if current == nil {
+		current = before
+	}
+	if before == nil {
+		before = &models.Category{}
+	}
+
+	activity := models.Activity{
+		EntityType:  models.ActivityEntityCategory,
+		EntityID:    current.ID,
+		EntityName:  current.Name,
+		WorkspaceID: current.WorkspaceID,
+		Action:      action,
+		UserID:      userID,
+		RequestID:   requestID,
+	}
+	if after != nil {
+		activity.Changes = appendChange(activity.Changes, "name", textValue(before.Name), textValue(after.Name))
+		activity.Changes = appendChange(activity.Changes, "description", textValue(before.Description), textValue(after.Description))
+		activity.Changes = appendChange(activity.Changes, "color", textValue(before.Color), textValue(after.Color))
+	}
+	return activity
+}
+
+// appendChange adds a field change when the old and new values differ
+func appendChange(changes []models.ActivityChange, field string, oldValue, newValue *string) []models.ActivityChange {
+	if oldValue == nil && newValue == nil {
+		return changes
+	}
+	if oldValue != nil && newValue != nil && *oldValue == *newValue {
+		return changes
+	}
+	return append(changes, models.ActivityChange{Field: field, OldValue: oldValue, NewValue: newValue})
+}
+
+// textValue records empty strings as no value
+func textValue(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// timeValue formats an optional time as RFC 3339 in UTC
+func timeValue(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+	return textValue(value.UTC().Format(time.RFC3339))
+}
+
+// intValue formats an optional number
+func intValue(value *int) *string {
+	if value == nil {
+		return nil
+	}
+	return textValue(strconv.Itoa(*value))
+}
+
+// idValue formats an optional ID; zero counts as no value
+func idValue(value *uint) *string {
+	if value == nil || *value == 0 {
+		return nil
+	}
+	return textValue(strconv.FormatUint(uint64(*value), 10))
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/lockout"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type AdminService struct {
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	statsRepo   *repository.StatsRepository
+	loginGuard  *lockout.Guard
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(userRepo *repository.UserRepository, sessionRepo *repository.SessionRepository, statsRepo *repository.StatsRepository, loginGuard *lockout.Guard) *AdminService {
+	return &AdminService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		statsRepo:   statsRepo,
+		loginGuard:  loginGuard,
+	}
+}
+
+// ListUsers retrieves users with search, filtering and pagination
+func (s *AdminService) ListUsers(filter models.AdminUserFilter) ([]models.User, int64, error) {
+	filter.SetDefaults()
+	return s.userRepo.FindAll(filter)
+}
+
+// GetUser retrieves a single user
+func (s *AdminService) GetUser(id uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// DisableUser disables an account and revokes all of its sessions
+func (s *AdminService) DisableUser(adminID, id uint) (*models.User, error) {
+	if adminID == id {
+		return nil, utils.ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if !user.IsDisabled() {
+		now := time.Now()
+		if err := s.userRepo.UpdateDisabledAt(user.ID, &now); err != nil {
+			return nil, err
+		}
+		user.DisabledAt = &now
+	}
+
+	if _, err := s.sessionRepo.RevokeAllByUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// EnableUser re-enables a disabled account
+func (s *AdminService) EnableUser(id uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if user.IsDisabled() {
+		if err := s.userRepo.UpdateDisabledAt(user.ID, nil); err != nil {
+			return nil, err
+		}
+		user.DisabledAt = nil
+	}
+
+	return user, nil
+}
+
+// UpdateUserRole changes the role of a user and revokes their sessions so the new role applies immediately
+func (s *AdminService) UpdateUserRole(adminID, id uint, role models.UserRole) (*models.User, error) {
+	if adminID == id && role != models.UserRoleAdmin {
+		return nil, utils.ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateRole(user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	if _, err := s.sessionRepo.RevokeAllByUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ForceLogout revokes every active session of a user and returns how many were revoked
+func (s *AdminService) ForceLogout(id uint) (int64, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return 0, utils.ErrUserNotFound
+	}
+	return s.sessionRepo.RevokeAllByUser(id)
+}
+
+// UnlockUser lifts a login lockout and clears the failed attempts of a user.
+// It returns the lockout status from before the unlock.
+func (s *AdminService) UnlockUser(id uint) (*lockout.Status, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	status, err := s.loginGuard.UserStatus(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Unlock(user.Username); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetSystemStats retrieves system-wide statistics
+func (s *AdminService) GetSystemStats() (*models.SystemStats, error) {
+	return s.statsRepo.GetSystemStats()
+}
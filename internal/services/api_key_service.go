@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// lastUsedResolution limits how often last_used_at is written for busy keys
+const lastUsedResolution = time.Minute
+
+type APIKeyService struct {
+	apiKeyRepo *repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo *repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Create mints a new API key. The raw key is only returned here.
+func (s *APIKeyService) Create(userID uint, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("api key name is required")
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate api key")
+	}
+	rawKey := models.APIKeyPrefix + secret
+
+	key := &models.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  rawKey[:len(models.APIKeyPrefix)+8],
+		KeyHash: utils.HashToken(rawKey),
+	}
+	key.SetScopes(uniqueScopes(req.Scopes))
+
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, errors.New("failed to create api key")
+	}
+
+	return &models.CreateAPIKeyResponse{
+		Key:    rawKey,
+		APIKey: *key,
+	}, nil
+}
+
+// GetAllByUser lists the API keys of a user
+func (s *APIKeyService) GetAllByUser(userID uint) ([]models.APIKey, error) {
+	return s.apiKeyRepo.FindAllByUser(userID)
+}
+
+// Revoke revokes an API key of a user
+func (s *APIKeyService) Revoke(id uint, userID uint) error {
+	if err := s.apiKeyRepo.Revoke(id, userID); err != nil {
+		if err.Error() == "api key not found" {
+			return utils.ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Authenticate resolves a raw API key to an active key and its owner
+func (s *APIKeyService) Authenticate(rawKey string) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.FindByHash(utils.HashToken(rawKey))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, utils.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if !key.IsActive() {
+		return nil, utils.ErrInvalidAPIKey
+	}
+	if key.User.IsDisabled() {
+		return nil, utils.ErrAccountDisabled
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.apiKeyRepo.TouchLastUsed(key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+// uniqueScopes removes duplicate scopes while keeping their order
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/storage"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// maxFileNameLength matches the size of the file_name column
+const maxFileNameLength = 255
+
+// AttachmentOptions holds attachment limits
+type AttachmentOptions struct {
+	MaxSize      int64    // largest accepted file in bytes
+	Quota        int64    // total bytes a user may upload to tasks that are not deleted
+	AllowedTypes []string // accepted MIME types
+}
+
+type AttachmentService struct {
+	attachmentRepo  *repository.AttachmentRepository
+	taskRepo        *repository.TaskRepository
+	workspaceRepo   *repository.WorkspaceRepository
+	activityService *ActivityService
+	store           storage.BlobStore
+	opts            AttachmentOptions
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(attachmentRepo *repository.AttachmentRepository, taskRepo *repository.TaskRepository, workspaceRepo *repository.WorkspaceRepository, activityService *ActivityService, store storage.BlobStore, opts AttachmentOptions) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo:  attachmentRepo,
+		taskRepo:        taskRepo,
+		workspaceRepo:   workspaceRepo,
+		activityService: activityService,
+		store:           store,
+		opts:            opts,
+	}
+}
+
+// MaxSize returns the largest accepted file in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.opts.MaxSize
+}
+
+// Upload stores a file and attaches it to a task the user can edit. The content is streamed
+// to the blob store while its checksum is computed.
+func (s *AttachmentService) Upload(ctx context.Context, taskID uint, userID uint, header *multipart.FileHeader, requestID string) (*models.Attachment, error) {
+	task, err := s.taskRepo.FindByID(taskID, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	// Check the limits before reading the file
+	if header.Size == 0 {
+		return nil, utils.ErrAttachmentEmpty
+	}
+	if header.Size > s.opts.MaxSize {
+		return nil, utils.ErrAttachmentTooLarge
+	}
+	used, err := s.attachmentRepo.SumSizeByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if used+header.Size > s.opts.Quota {
+		return nil, utils.ErrAttachmentQuotaExceeded
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The first bytes decide the content type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := detectContentType(head, header.Header.Get("Content-Type"), header.Filename)
+	if !s.allowedType(contentType) {
+		return nil, utils.ErrAttachmentTypeNotAllowed
+	}
+
+	token, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("tasks/%d/%s", taskID, token)
+
+	hash := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(head), file), hash)
+	if err := s.store.Put(ctx, key, content, header.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	attachment := &models.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		FileName:    cleanFileName(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.deleteBlob(key)
+		return nil, err
+	}
+
+	s.activityService.RecordTaskAttachments(task, []string{attachment.FileName}, nil, userID, requestID)
+
+	// Reload with the uploader
+	return s.attachmentRepo.FindByID(attachment.ID, taskID)
+}
+
+// GetAllByTask retrieves the attachments of a task visible to the user, oldest first
+func (s *AttachmentService) GetAllByTask(taskID uint, userID uint) ([]models.Attachment, error) {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrTaskNotFound
+	}
+	return s.attachmentRepo.FindAllByTask(taskID)
+}
+
+// Open retrieves an attachment with its content for download. Everyone who can see the task
+// may download its attachments; the caller closes the content.
+func (s *AttachmentService) Open(ctx context.Context, id uint, taskID uint, userID uint) (*models.Attachment, io.ReadCloser, error) {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, utils.ErrTaskNotFound
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(id, taskID)
+	if err != nil {
+		return nil, nil, utils.ErrAttachmentNotFound
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Printf("❌ Content of attachment %d is missing from the blob store", attachment.ID)
+			return nil, nil, utils.ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// Delete removes an attachment from a task the user can edit and deletes its content
+func (s *AttachmentService) Delete(id uint, taskID uint, userID uint, requestID string) error {
+	task, err := s.taskRepo.FindByID(taskID, userID)
+	if err != nil {
+		return utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(id, taskID)
+	if err != nil {
+		return utils.ErrAttachmentNotFound
+	}
+	if err := s.attachmentRepo.Delete(attachment.ID); err != nil {
+		return err
+	}
+	s.deleteBlob(attachment.StorageKey)
+
+	s.activityService.RecordTaskAttachments(task, nil, []string{attachment.FileName}, userID, requestID)
+	return nil
+}
+
+// allowedType checks a MIME type against the configured list
+func (s *AttachmentService) allowedType(contentType string) bool {
+	for _, allowed := range s.opts.AllowedTypes {
+		if strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBlob removes stored content. Failures only leave an orphaned blob behind, so they are logged.
+func (s *AttachmentService) deleteBlob(key string) {
+	if err := s.store.Delete(context.Background(), key); err != nil {
+		log.Printf("❌ Failed to delete blob %s: %v", key, err)
+	}
+}
+
+// detectContentType decides the MIME type of an upload from its first bytes, so that a file
+// cannot pass as an allowed type by its name alone. Plain text may be refined to another text
+// type (markdown, CSV) declared by the client or implied by the file extension.
+func detectContentType(head []byte, declared string, fileName string) string {
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	if sniffed != "text/plain" {
+		return sniffed
+	}
+
+	if declared == "" || declared == "application/octet-stream" {
+		declared = mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	}
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && strings.HasPrefix(mediaType, "text/") {
+		return mediaType
+	}
+	return sniffed
+}
+
+// cleanFileName keeps the base name of an uploaded file without control characters
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+
+	// Shorten long names, keeping whole characters
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
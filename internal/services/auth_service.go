@@ -2,28 +2,67 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"strings"
+	"time"
 
+	"github.com/hoanghnt/TaskManagementAPI/internal/lockout"
+	"github.com/hoanghnt/TaskManagementAPI/internal/mailer"
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
 
+// Two-factor login challenge settings
+const (
+	twoFactorChallengeExpiry      = 5 * time.Minute
+	twoFactorChallengeMaxAttempts = 5
+)
+
+// AuthOptions holds token lifetimes and settings for the auth service
+type AuthOptions struct {
+	AccessExpiry        time.Duration
+	RefreshExpiry       time.Duration
+	PasswordResetExpiry time.Duration
+	VerificationExpiry  time.Duration
+	UnlockExpiry        time.Duration
+	BaseURL             string
+}
+
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtExpiry int
+	userRepo      *repository.UserRepository
+	sessionRepo   *repository.SessionRepository
+	userTokenRepo *repository.UserTokenRepository
+	twoFactor     *TwoFactorService
+	loginGuard    *lockout.Guard
+	mailer        mailer.Mailer
+	opts          AuthOptions
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo *repository.UserRepository, jwtExpiry int) *AuthService {
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	userTokenRepo *repository.UserTokenRepository,
+	twoFactor *TwoFactorService,
+	loginGuard *lockout.Guard,
+	mailer mailer.Mailer,
+	opts AuthOptions,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtExpiry: jwtExpiry,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		userTokenRepo: userTokenRepo,
+		twoFactor:     twoFactor,
+		loginGuard:    loginGuard,
+		mailer:        mailer,
+		opts:          opts,
 	}
 }
 
 // Register registers a new user
-func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthResponse, error) {
+func (s *AuthService) Register(req *models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error) {
 	// Validate input
 	req.Username = strings.TrimSpace(req.Username)
 	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
@@ -53,6 +92,7 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 		Email:    req.Email,
 		Password: req.Password,
 		FullName: req.FullName,
+		Role:     models.UserRoleUser,
 	}
 
 	// Hash password
@@ -65,44 +105,450 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 		return nil, errors.New("failed to create user")
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username, s.jwtExpiry)
-	if err != nil {
-		return nil, errors.New("failed to generate token")
+	// Registration succeeds even if the verification email cannot be sent; the user can ask for a resend
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("❌ Failed to send verification email to user %d: %v", user.ID, err)
 	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return s.startSession(user, client)
 }
 
-// Login authenticates a user and returns JWT token
-func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
+// Login authenticates a user and returns JWT token.
+// If the user has two-factor authentication enabled, a challenge is returned instead
+// which must be completed with CompleteTwoFactorLogin.
+// Repeated failures are throttled per username and per IP; a *lockout.Error is returned while blocked.
+func (s *AuthService) Login(req *models.LoginRequest, client models.ClientInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
 	// Validate input
 	req.Username = strings.TrimSpace(req.Username)
 
+	// Reject before touching bcrypt while the username or IP is blocked
+	if err := s.loginGuard.Check(req.Username, client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+
 	// Find user by username
 	user, err := s.userRepo.FindByUsername(req.Username)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		// Unknown usernames are tracked too so lockouts do not reveal which accounts exist
+		return nil, nil, s.recordLoginFailure(req.Username, nil, client)
 	}
 
 	// Check password
 	if !user.CheckPassword(req.Password) {
-		return nil, errors.New("invalid credentials")
+		return nil, nil, s.recordLoginFailure(req.Username, user, client)
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username, s.jwtExpiry)
+	return s.LoginUser(user, client)
+}
+
+// LoginUser finishes a login for a user whose primary credentials were already verified,
+// either by password or by an external identity provider. It returns a two-factor challenge
+// instead of tokens when the user has two-factor authentication enabled.
+func (s *AuthService) LoginUser(user *models.User, client models.ClientInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	if user.IsDisabled() {
+		return nil, nil, utils.ErrAccountDisabled
+	}
+
+	if user.IsTwoFactorEnabled() {
+		challengeToken, err := s.createUserToken(user.ID, models.TokenPurposeTwoFactorLogin, twoFactorChallengeExpiry)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &models.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			ExpiresAt:         time.Now().Add(twoFactorChallengeExpiry),
+		}, nil
+	}
+
+	// Failures are only cleared once the login is complete, so 2FA users are reset in CompleteTwoFactorLogin
+	if err := s.loginGuard.RecordSuccess(user.Username); err != nil {
+		return nil, nil, err
+	}
+
+	authResponse, err := s.startSession(user, client)
+	return authResponse, nil, err
+}
+
+// CompleteTwoFactorLogin exchanges a login challenge and a TOTP or recovery code for tokens
+func (s *AuthService) CompleteTwoFactorLogin(req *models.TwoFactorLoginRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	challenge, err := s.userTokenRepo.FindValid(utils.HashToken(strings.TrimSpace(req.ChallengeToken)), models.TokenPurposeTwoFactorLogin)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return nil, utils.ErrInvalidChallenge
 	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, utils.ErrInvalidChallenge
+	}
+	if user.IsDisabled() {
+		return nil, utils.ErrAccountDisabled
+	}
+
+	if err := s.loginGuard.Check(user.Username, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.twoFactor.VerifySecondFactor(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Wrong codes count against the account like wrong passwords
+		if err := s.recordLoginFailure(user.Username, user, client); !errors.Is(err, utils.ErrInvalidCredentials) {
+			return nil, err
+		}
+
+		// Burn the challenge after too many wrong codes
+		attempts, err := s.userTokenRepo.IncrementAttempts(challenge.ID)
+		if err != nil {
+			return nil, err
+		}
+		if attempts >= twoFactorChallengeMaxAttempts {
+			if _, err := s.userTokenRepo.MarkUsed(challenge.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	marked, err := s.userTokenRepo.MarkUsed(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, utils.ErrInvalidChallenge
+	}
+
+	if err := s.loginGuard.RecordSuccess(user.Username); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, client)
+}
+
+// Refresh exchanges a refresh token for a new access/refresh token pair.
+// Refresh tokens are single use: presenting an already used token revokes the whole session.
+func (s *AuthService) Refresh(refreshToken string, client models.ClientInfo) (*models.AuthResponse, error) {
+	token, err := s.sessionRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+
+	// Reuse detection: a used token means it was stolen or replayed
+	if token.UsedAt != nil {
+		if err := s.sessionRepo.Revoke(token.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrRefreshTokenReused
+	}
+
+	if !token.Session.IsActive() {
+		return nil, utils.ErrSessionRevoked
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+
+	// Mark as used; losing the race against a concurrent refresh counts as reuse
+	marked, err := s.sessionRepo.MarkRefreshTokenUsed(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		if err := s.sessionRepo.Revoke(token.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+	if user.IsDisabled() {
+		return nil, utils.ErrAccountDisabled
+	}
+
+	if err := s.sessionRepo.Touch(token.SessionID); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, &token.Session)
+}
+
+// Logout revokes the given session so its access and refresh tokens stop working
+func (s *AuthService) Logout(sessionID string) error {
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// VerifyEmail confirms the email address that a verification token was sent to
+func (s *AuthService) VerifyEmail(rawToken string) (*models.User, error) {
+	token, err := s.userTokenRepo.FindValid(utils.HashToken(strings.TrimSpace(rawToken)), models.TokenPurposeEmailVerification)
+	if err != nil {
+		return nil, utils.ErrInvalidVerificationToken
+	}
+
+	marked, err := s.userTokenRepo.MarkUsed(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, utils.ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, utils.ErrInvalidVerificationToken
+	}
+
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	// The link only verifies the address it was sent to
+	now := time.Now()
+	verified, err := s.userRepo.MarkEmailVerified(user.ID, user.Email, now)
+	if err != nil {
+		return nil, errors.New("failed to verify email")
+	}
+	if !verified {
+		return nil, utils.ErrInvalidVerificationToken
+	}
+	user.EmailVerifiedAt = &now
+
+	return user, nil
+}
+
+// ResendVerificationEmail sends a new verification link, invalidating previous ones
+func (s *AuthService) ResendVerificationEmail(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if user.IsEmailVerified() {
+		return utils.ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+// ForgotPassword emails a password reset link if an account exists for the email.
+// It never reveals whether the email is registered.
+func (s *AuthService) ForgotPassword(req *models.ForgotPasswordRequest) error {
+	email := strings.TrimSpace(strings.ToLower(req.Email))
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	// Only the most recent reset link stays valid
+	if err := s.userTokenRepo.InvalidateAll(user.ID, models.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	rawToken, err := s.createUserToken(user.ID, models.TokenPurposePasswordReset, s.opts.PasswordResetExpiry)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Use the link below to choose a new one:\n\n%s/reset-password?token=%s\n\nReset token: %s\n\nThis link expires in %d minutes. If you did not request a reset, you can ignore this email.\n",
+			user.FullName, s.opts.BaseURL, rawToken, rawToken, int(s.opts.PasswordResetExpiry.Minutes()),
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		// Do not leak delivery failures to the caller
+		log.Printf("❌ Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and revokes all sessions
+func (s *AuthService) ResetPassword(req *models.ResetPasswordRequest) error {
+	token, err := s.userTokenRepo.FindValid(utils.HashToken(strings.TrimSpace(req.Token)), models.TokenPurposePasswordReset)
+	if err != nil {
+		return utils.ErrInvalidResetToken
+	}
+
+	marked, err := s.userTokenRepo.MarkUsed(token.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return utils.ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return utils.ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(user, req.NewPassword); err != nil {
+		return err
+	}
+
+	// Sign out everywhere, the old password may have been compromised
+	if _, err := s.sessionRepo.RevokeAllByUser(user.ID); err != nil {
+		return err
+	}
+
+	// Proving access to the mailbox also lifts a login lockout
+	if err := s.loginGuard.Unlock(user.Username); err != nil {
+		return err
+	}
+
+	s.sendPasswordChangedEmail(user)
+	return nil
+}
+
+// UnlockAccount lifts a login lockout using the unlock token emailed when the account was locked
+func (s *AuthService) UnlockAccount(req *models.UnlockAccountRequest) error {
+	token, err := s.userTokenRepo.FindValid(utils.HashToken(strings.TrimSpace(req.Token)), models.TokenPurposeAccountUnlock)
+	if err != nil {
+		return utils.ErrInvalidUnlockToken
+	}
+
+	marked, err := s.userTokenRepo.MarkUsed(token.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return utils.ErrInvalidUnlockToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return utils.ErrInvalidUnlockToken
+	}
+
+	return s.loginGuard.Unlock(user.Username)
+}
+
+// ChangePassword changes the password of a logged in user and revokes their other sessions
+func (s *AuthService) ChangePassword(userID uint, sessionID string, req *models.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.CheckPassword(req.CurrentPassword) {
+		return utils.ErrIncorrectPassword
+	}
+	if req.CurrentPassword == req.NewPassword {
+		return utils.ErrPasswordUnchanged
+	}
+
+	if err := s.setPassword(user, req.NewPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.RevokeAllByUserExcept(user.ID, sessionID); err != nil {
+		return err
+	}
+
+	s.sendPasswordChangedEmail(user)
+	return nil
+}
+
+// UpdateProfile changes the username, email and/or full name of a user.
+// A new email address must be verified again; the old address is notified of the change.
+func (s *AuthService) UpdateProfile(userID uint, req *models.UpdateProfileRequest) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username != user.Username {
+			exists, err := s.userRepo.ExistsByUsername(username)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.New("username already exists")
+			}
+			user.Username = username
+		}
+	}
+
+	oldEmail := user.Email
+	emailChanged := false
+	if req.Email != nil {
+		email := strings.TrimSpace(strings.ToLower(*req.Email))
+		if email != user.Email {
+			exists, err := s.userRepo.ExistsByEmail(email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.New("email already exists")
+			}
+			user.Email = email
+			user.EmailVerifiedAt = nil
+			emailChanged = true
+		}
+	}
+
+	if req.FullName != nil {
+		user.FullName = strings.TrimSpace(*req.FullName)
+	}
+
+	if err := s.userRepo.UpdateProfile(user, emailChanged); err != nil {
+		return nil, errors.New("failed to update profile")
+	}
+
+	if emailChanged {
+		// Links sent to the old address must not work anymore
+		if err := s.userTokenRepo.InvalidateAll(user.ID, models.TokenPurposePasswordReset); err != nil {
+			return nil, err
+		}
+		if err := s.sendVerificationEmail(user); err != nil {
+			log.Printf("❌ Failed to send verification email to user %d: %v", user.ID, err)
+		}
+		s.sendEmailChangedEmail(user, oldEmail)
+	}
+
+	return user, nil
+}
+
+// DeleteAccount deletes the account of a user after confirming their password.
+// Personal tasks and categories are soft deleted and all sessions, refresh tokens and API keys are revoked.
+// Workspaces shared with other members have to be transferred or deleted first.
+func (s *AuthService) DeleteAccount(userID uint, req *models.DeleteAccountRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.CheckPassword(req.Password) {
+		return utils.ErrIncorrectPassword
+	}
+
+	ownsShared, err := s.userRepo.OwnsSharedWorkspace(user.ID)
+	if err != nil {
+		return errors.New("failed to delete account")
+	}
+	if ownsShared {
+		return utils.ErrOwnsSharedWorkspaces
+	}
+
+	if err := s.userRepo.DeleteAccount(user.ID); err != nil {
+		return errors.New("failed to delete account")
+	}
+
+	if err := s.loginGuard.Unlock(user.Username); err != nil {
+		log.Printf("❌ Failed to clear login attempts of deleted user %d: %v", user.ID, err)
+	}
+
+	return nil
 }
 
 // GetUserByID retrieves user by ID
@@ -113,3 +559,194 @@ func (s *AuthService) GetUserByID(userID uint) (*models.User, error) {
 	}
 	return user, nil
 }
+
+// startSession opens a new session for the user and issues its first token pair
+func (s *AuthService) startSession(user *models.User, client models.ClientInfo) (*models.AuthResponse, error) {
+	sessionID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  client.IPAddress,
+		ExpiresAt:  now.Add(s.opts.RefreshExpiry),
+		LastUsedAt: now,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return s.issueTokens(user, session)
+}
+
+// issueTokens issues an access token and a rotated refresh token for a session
+func (s *AuthService) issueTokens(user *models.User, session *models.Session) (*models.AuthResponse, error) {
+	rawRefresh, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	refreshToken := &models.RefreshToken{
+		SessionID: session.ID,
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(rawRefresh),
+		ExpiresAt: session.ExpiresAt,
+	}
+	if err := s.sessionRepo.CreateRefreshToken(refreshToken); err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	// Generate JWT token
+	token, err := utils.GenerateToken(user.ID, user.Username, string(user.Role), session.ID, s.opts.AccessExpiry)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &models.AuthResponse{
+		Token:        token,
+		RefreshToken: rawRefresh,
+		ExpiresAt:    time.Now().Add(s.opts.AccessExpiry),
+		User:         *user,
+	}, nil
+}
+
+// setPassword hashes and stores a new password for the user
+func (s *AuthService) setPassword(user *models.User, password string) error {
+	user.Password = password
+	if err := user.HashPassword(); err != nil {
+		return errors.New("failed to hash password")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, user.Password); err != nil {
+		return errors.New("failed to update password")
+	}
+	return nil
+}
+
+// createUserToken stores a new single-use token and returns its raw value
+func (s *AuthService) createUserToken(userID uint, purpose models.TokenPurpose, expiry time.Duration) (string, error) {
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	token := &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	if err := s.userTokenRepo.Create(token); err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	return rawToken, nil
+}
+
+// sendVerificationEmail creates a verification token and emails the verification link
+func (s *AuthService) sendVerificationEmail(user *models.User) error {
+	// Only the most recent verification link stays valid
+	if err := s.userTokenRepo.InvalidateAll(user.ID, models.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	rawToken, err := s.createUserToken(user.ID, models.TokenPurposeEmailVerification, s.opts.VerificationExpiry)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s/api/v1/auth/verify?token=%s\n\nThis link expires in %d hours.\n",
+			user.FullName, s.opts.BaseURL, rawToken, int(s.opts.VerificationExpiry.Hours()),
+		),
+	}
+	return s.mailer.Send(msg)
+}
+
+// sendPasswordChangedEmail notifies the user that their password was changed
+func (s *AuthService) sendPasswordChangedEmail(user *models.User) {
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe password for your account %q was just changed. If this was not you, reset your password immediately.\n",
+			user.FullName, user.Username,
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("❌ Failed to send password changed email to user %d: %v", user.ID, err)
+	}
+}
+
+// recordLoginFailure registers a failed login attempt. It returns a *lockout.Error if this failure
+// locked the account, ErrInvalidCredentials otherwise. The owner of an existing account is
+// emailed an unlock link when the lockout starts.
+func (s *AuthService) recordLoginFailure(username string, user *models.User, client models.ClientInfo) error {
+	status, err := s.loginGuard.RecordFailure(username, client.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	if !status.Locked {
+		return utils.ErrInvalidCredentials
+	}
+
+	if user != nil {
+		if err := s.sendUnlockEmail(user, status.RetryAfter); err != nil {
+			log.Printf("❌ Failed to send unlock email to user %d: %v", user.ID, err)
+		}
+	}
+
+	return &lockout.Error{Err: lockout.ErrLocked, RetryAfter: status.RetryAfter}
+}
+
+// sendUnlockEmail creates an unlock token and emails the unlock link
+func (s *AuthService) sendUnlockEmail(user *models.User, lockedFor time.Duration) error {
+	if err := s.userTokenRepo.InvalidateAll(user.ID, models.TokenPurposeAccountUnlock); err != nil {
+		return err
+	}
+
+	rawToken, err := s.createUserToken(user.ID, models.TokenPurposeAccountUnlock, s.opts.UnlockExpiry)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your account has been locked",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe locked your account %q for %d minutes after too many failed login attempts.\n\nIf this was you, you can unlock it right away with the link below:\n\n%s/unlock-account?token=%s\n\nUnlock token: %s\n\nIf this was not you, consider resetting your password.\n",
+			user.FullName, user.Username, int(lockedFor.Round(time.Minute).Minutes()), s.opts.BaseURL, rawToken, rawToken,
+		),
+	}
+	return s.mailer.Send(msg)
+}
+
+// sendEmailChangedEmail notifies the previous address that the account email was changed
+func (s *AuthService) sendEmailChangedEmail(user *models.User, oldEmail string) {
+	msg := mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email address of your account %q was changed to %s. If this was not you, contact support immediately.\n",
+			user.FullName, user.Username, user.Email,
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("❌ Failed to send email changed notification to user %d: %v", user.ID, err)
+	}
+}
+
+// truncate shortens a string to at most limit bytes
+func truncate(value string, limit int) string {
+	if len(value) > limit {
+		return value[:limit]
+	}
+	return value
+}
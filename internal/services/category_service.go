@@ -6,21 +6,26 @@ import (
 
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
 
 type CategoryService struct {
-	categoryRepo *repository.CategoryRepository
+	categoryRepo    *repository.CategoryRepository
+	workspaceRepo   *repository.WorkspaceRepository
+	activityService *ActivityService
 }
 
 // NewCategoryService creates a new category service
-func NewCategoryService(categoryRepo *repository.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo *repository.CategoryRepository, workspaceRepo *repository.WorkspaceRepository, activityService *ActivityService) *CategoryService {
 	return &CategoryService{
-		categoryRepo: categoryRepo,
+		categoryRepo:    categoryRepo,
+		workspaceRepo:   workspaceRepo,
+		activityService: activityService,
 	}
 }
 
-// Create creates a new category
-func (s *CategoryService) Create(req *models.CreateCategoryRequest, userID uint) (*models.Category, error) {
+// Create creates a new category. requestID ties the recorded activity to the HTTP request.
+func (s *CategoryService) Create(req *models.CreateCategoryRequest, userID uint, requestID string) (*models.Category, error) {
 	// Validate and sanitize input
 	req.Name = strings.TrimSpace(req.Name)
 	req.Description = strings.TrimSpace(req.Description)
@@ -30,8 +35,16 @@ func (s *CategoryService) Create(req *models.CreateCategoryRequest, userID uint)
 		return nil, errors.New("category name is required")
 	}
 
-	// Check if category with same name exists for this user
-	exists, err := s.categoryRepo.ExistsByNameAndUser(req.Name, userID)
+	workspaceID := req.WorkspaceID
+	if workspaceID != nil && *workspaceID == 0 {
+		workspaceID = nil
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	// Check if category with same name exists for this user or workspace
+	exists, err := s.categoryRepo.ExistsByNameAndUser(req.Name, userID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +58,15 @@ func (s *CategoryService) Create(req *models.CreateCategoryRequest, userID uint)
 		Description: req.Description,
 		Color:       req.Color,
 		UserID:      userID,
+		WorkspaceID: workspaceID,
 	}
 
 	if err := s.categoryRepo.Create(category); err != nil {
 		return nil, errors.New("failed to create category")
 	}
 
+	s.activityService.RecordCategory(models.ActivityActionCreated, nil, category, userID, requestID)
+
 	return category, nil
 }
 
@@ -63,8 +79,9 @@ func (s *CategoryService) GetByID(id uint, userID uint) (*models.Category, error
 	return category, nil
 }
 
-// GetAllByUser retrieves all categories for a user with pagination
-func (s *CategoryService) GetAllByUser(userID uint, page, pageSize int) ([]models.Category, int64, error) {
+// GetAllByUser retrieves all categories visible to a user with pagination.
+// A non-zero workspaceID limits the result to that workspace.
+func (s *CategoryService) GetAllByUser(userID uint, workspaceID uint, page, pageSize int) ([]models.Category, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -73,8 +90,18 @@ func (s *CategoryService) GetAllByUser(userID uint, page, pageSize int) ([]model
 		pageSize = 10
 	}
 
+	if workspaceID > 0 {
+		isMember, err := s.workspaceRepo.IsMember(workspaceID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !isMember {
+			return nil, 0, utils.ErrWorkspaceNotFound
+		}
+	}
+
 	// Use the new method with task counts
-	categories, total, err := s.categoryRepo.GetCategoriesWithTaskCount(userID, page, pageSize)
+	categories, total, err := s.categoryRepo.GetCategoriesWithTaskCount(userID, workspaceID, page, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -83,19 +110,23 @@ func (s *CategoryService) GetAllByUser(userID uint, page, pageSize int) ([]model
 }
 
 // Update updates a category
-func (s *CategoryService) Update(id uint, req *models.UpdateCategoryRequest, userID uint) (*models.Category, error) {
+func (s *CategoryService) Update(id uint, req *models.UpdateCategoryRequest, userID uint, requestID string) (*models.Category, error) {
 	// Find existing category
 	category, err := s.categoryRepo.FindByID(id, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, category.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+	before := *category
 
 	// Update fields if provided
 	if req.Name != "" {
 		req.Name = strings.TrimSpace(req.Name)
 
 		// Check if new name conflicts with existing category
-		exists, err := s.categoryRepo.ExistsByNameAndUserExcludingID(req.Name, userID, id)
+		exists, err := s.categoryRepo.ExistsByNameAndUserExcludingID(req.Name, userID, category.WorkspaceID, id)
 		if err != nil {
 			return nil, err
 		}
@@ -119,10 +150,25 @@ func (s *CategoryService) Update(id uint, req *models.UpdateCategoryRequest, use
 		return nil, errors.New("failed to update category")
 	}
 
+	s.activityService.RecordCategory(models.ActivityActionUpdated, &before, category, userID, requestID)
+
 	return category, nil
 }
 
 // Delete deletes a category
-func (s *CategoryService) Delete(id uint, userID uint) error {
-	return s.categoryRepo.Delete(id, userID)
+func (s *CategoryService) Delete(id uint, userID uint, requestID string) error {
+	category, err := s.categoryRepo.FindByID(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, category.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	if err := s.categoryRepo.Delete(id, userID); err != nil {
+		return err
+	}
+
+	s.activityService.RecordCategory(models.ActivityActionDeleted, category, nil, userID, requestID)
+	return nil
 }
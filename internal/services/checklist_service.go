@@ -0,0 +1,153 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// maxChecklistItems limits the checklist of a task; longer lists call for subtasks
+const maxChecklistItems = 100
+
+type ChecklistService struct {
+	checklistRepo *repository.ChecklistRepository
+	taskRepo      *repository.TaskRepository
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewChecklistService creates a new checklist service
+func NewChecklistService(checklistRepo *repository.ChecklistRepository, taskRepo *repository.TaskRepository, workspaceRepo *repository.WorkspaceRepository) *ChecklistService {
+	return &ChecklistService{
+		checklistRepo: checklistRepo,
+		taskRepo:      taskRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// GetAllByTask retrieves the checklist of a task visible to the user, in order
+func (s *ChecklistService) GetAllByTask(taskID uint, userID uint) ([]models.ChecklistItem, error) {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrTaskNotFound
+	}
+	return s.checklistRepo.FindAllByTask(taskID)
+}
+
+// Create adds an item to the checklist of a task the user can edit, at the end unless a position is given
+func (s *ChecklistService) Create(taskID uint, userID uint, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, utils.ErrChecklistItemEmpty
+	}
+
+	count, err := s.checklistRepo.CountByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxChecklistItems {
+		return nil, utils.ErrChecklistFull
+	}
+
+	position := int(count)
+	if req.Position != nil && *req.Position < position {
+		position = *req.Position
+	}
+
+	item := &models.ChecklistItem{
+		TaskID:   taskID,
+		Text:     text,
+		Position: position,
+	}
+	if err := s.checklistRepo.Create(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Update changes the text of an item or checks it off
+func (s *ChecklistService) Update(id uint, taskID uint, userID uint, req models.UpdateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.checklistRepo.FindByID(id, taskID)
+	if err != nil {
+		return nil, utils.ErrChecklistItemNotFound
+	}
+
+	if req.Text != "" {
+		text := strings.TrimSpace(req.Text)
+		if text == "" {
+			return nil, utils.ErrChecklistItemEmpty
+		}
+		item.Text = text
+	}
+	if req.Done != nil && *req.Done != item.Done {
+		item.Done = *req.Done
+		if item.Done {
+			now := time.Now()
+			item.DoneAt = &now
+		} else {
+			item.DoneAt = nil
+		}
+	}
+
+	if err := s.checklistRepo.Update(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Delete removes an item from the checklist of a task the user can edit
+func (s *ChecklistService) Delete(id uint, taskID uint, userID uint) error {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return err
+	}
+
+	item, err := s.checklistRepo.FindByID(id, taskID)
+	if err != nil {
+		return utils.ErrChecklistItemNotFound
+	}
+	return s.checklistRepo.Delete(item)
+}
+
+// Reorder puts the checklist of a task in the given order. The list must contain every item
+// of the checklist exactly once.
+func (s *ChecklistService) Reorder(taskID uint, userID uint, req models.ReorderChecklistRequest) ([]models.ChecklistItem, error) {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.checklistRepo.FindAllByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ItemIDs) != len(items) {
+		return nil, utils.ErrChecklistOrderMismatch
+	}
+	remaining := make(map[uint]bool, len(items))
+	for _, item := range items {
+		remaining[item.ID] = true
+	}
+	for _, id := range req.ItemIDs {
+		if !remaining[id] {
+			return nil, utils.ErrChecklistOrderMismatch
+		}
+		delete(remaining, id)
+	}
+
+	if err := s.checklistRepo.Reorder(taskID, req.ItemIDs); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.FindAllByTask(taskID)
+}
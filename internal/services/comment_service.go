@@ -0,0 +1,122 @@
+package services
+
+import (
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type CommentService struct {
+	commentRepo         *repository.CommentRepository
+	taskRepo            *repository.TaskRepository
+	notificationService *NotificationService
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(commentRepo *repository.CommentRepository, taskRepo *repository.TaskRepository, notificationService *NotificationService) *CommentService {
+	return &CommentService{
+		commentRepo:         commentRepo,
+		taskRepo:            taskRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Create adds a comment to a task. Everyone who can see the task may comment on it,
+// including workspace viewers.
+func (s *CommentService) Create(taskID uint, userID uint, req models.CreateCommentRequest) (*models.Comment, error) {
+	task, err := s.taskRepo.FindByID(taskID, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+
+	comment := &models.Comment{
+		TaskID: taskID,
+		UserID: userID,
+		Body:   req.Body,
+	}
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	s.notificationService.NotifyMentions(userID, task, &comment.ID, "", comment.Body)
+
+	// Reload with the author
+	return s.commentRepo.FindByID(comment.ID, taskID)
+}
+
+// GetAllByTask retrieves the comments of a task with pagination
+func (s *CommentService) GetAllByTask(taskID uint, userID uint, filter models.CommentFilter) ([]models.Comment, int64, error) {
+	if err := s.requireTask(taskID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	filter.SetDefaults()
+	return s.commentRepo.FindAllByTask(taskID, filter.Page, filter.PageSize)
+}
+
+// GetByID retrieves a comment with its edit history
+func (s *CommentService) GetByID(id uint, taskID uint, userID uint) (*models.Comment, error) {
+	if err := s.requireTask(taskID, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.FindByID(id, taskID)
+	if err != nil {
+		return nil, utils.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// Update changes the text of a comment; only its author may do so
+func (s *CommentService) Update(id uint, taskID uint, userID uint, req models.UpdateCommentRequest) (*models.Comment, error) {
+	task, err := s.taskRepo.FindByID(taskID, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	comment, err := s.commentRepo.FindByID(id, taskID)
+	if err != nil {
+		return nil, utils.ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return nil, utils.ErrNotCommentAuthor
+	}
+
+	// Nothing to record when the text did not change
+	if comment.Body == req.Body {
+		return comment, nil
+	}
+
+	previousBody := comment.Body
+	if err := s.commentRepo.UpdateBody(comment, req.Body); err != nil {
+		return nil, err
+	}
+
+	s.notificationService.NotifyMentions(userID, task, &comment.ID, previousBody, comment.Body)
+
+	return s.commentRepo.FindByID(id, taskID)
+}
+
+// Delete deletes a comment; only its author may do so
+func (s *CommentService) Delete(id uint, taskID uint, userID uint) error {
+	comment, err := s.GetByID(id, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return utils.ErrNotCommentAuthor
+	}
+
+	return s.commentRepo.Delete(comment.ID)
+}
+
+// requireTask checks that the task exists and is visible to the user
+func (s *CommentService) requireTask(taskID uint, userID uint) error {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.ErrTaskNotFound
+	}
+	return nil
+}
@@ -0,0 +1,550 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/mailer"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// How often expired archives are removed
+const dataExportCleanupInterval = time.Hour
+
+// DataExportOptions holds storage and link settings for data exports
+type DataExportOptions struct {
+	Dir        string
+	Retention  time.Duration
+	LinkExpiry time.Duration
+	BaseURL    string
+}
+
+type DataExportService struct {
+	exportRepo    *repository.DataExportRepository
+	userRepo      *repository.UserRepository
+	categoryRepo  *repository.CategoryRepository
+	taskRepo      *repository.TaskRepository
+	sessionRepo   *repository.SessionRepository
+	userTokenRepo *repository.UserTokenRepository
+	mailer        mailer.Mailer
+	opts          DataExportOptions
+	jobs          chan uint
+}
+
+// NewDataExportService creates a new data export service. Call Start to begin processing.
+func NewDataExportService(
+	exportRepo *repository.DataExportRepository,
+	userRepo *repository.UserRepository,
+	categoryRepo *repository.CategoryRepository,
+	taskRepo *repository.TaskRepository,
+	sessionRepo *repository.SessionRepository,
+	userTokenRepo *repository.UserTokenRepository,
+	mailer mailer.Mailer,
+	opts DataExportOptions,
+) *DataExportService {
+	return &DataExportService{
+		exportRepo:    exportRepo,
+		userRepo:      userRepo,
+		categoryRepo:  categoryRepo,
+		taskRepo:      taskRepo,
+		sessionRepo:   sessionRepo,
+		userTokenRepo: userTokenRepo,
+		mailer:        mailer,
+		opts:          opts,
+		jobs:          make(chan uint, 100),
+	}
+}
+
+// Start runs the background worker and cleanup until ctx is cancelled.
+// Exports that were queued or interrupted by a restart are picked up again.
+func (s *DataExportService) Start(ctx context.Context) error {
+	if err := os.MkdirAll(s.opts.Dir, 0o700); err != nil {
+		return err
+	}
+
+	ids, err := s.exportRepo.FindUnfinishedIDs()
+	if err != nil {
+		return err
+	}
+
+	go s.work(ctx)
+	go s.cleanup(ctx)
+
+	for _, id := range ids {
+		s.enqueue(id)
+	}
+	return nil
+}
+
+// RequestExport queues a new export of the user's data
+func (s *DataExportService) RequestExport(userID uint) (*models.DataExport, error) {
+	active, err := s.exportRepo.HasActive(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, utils.ErrDataExportInProgress
+	}
+
+	export := &models.DataExport{
+		UserID: userID,
+		Status: models.DataExportStatusPending,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, errors.New("failed to create data export")
+	}
+
+	s.enqueue(export.ID)
+	return export, nil
+}
+
+// GetAllByUser retrieves all exports of a user
+func (s *DataExportService) GetAllByUser(userID uint) ([]models.DataExport, error) {
+	return s.exportRepo.FindAllByUser(userID)
+}
+
+// GetExport retrieves an export. Completed exports get a fresh time-limited download link.
+func (s *DataExportService) GetExport(userID, id uint) (*models.DataExport, error) {
+	export, err := s.exportRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrDataExportNotFound
+	}
+
+	if export.IsDownloadable() {
+		if err := s.attachDownloadLink(export); err != nil {
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// OpenDownload validates a download link and returns the export and the path of its archive.
+// A link only opens the export it was issued for, and only once.
+func (s *DataExportService) OpenDownload(id uint, rawToken string) (*models.DataExport, string, error) {
+	token, err := s.userTokenRepo.FindValid(downloadTokenHash(id, strings.TrimSpace(rawToken)), models.TokenPurposeDataExport)
+	if err != nil {
+		return nil, "", utils.ErrInvalidDownloadToken
+	}
+
+	export, err := s.exportRepo.FindByID(id, token.UserID)
+	if err != nil {
+		return nil, "", utils.ErrInvalidDownloadToken
+	}
+	if !export.IsDownloadable() {
+		return nil, "", utils.ErrDataExportNotReady
+	}
+
+	marked, err := s.userTokenRepo.MarkUsed(token.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !marked {
+		return nil, "", utils.ErrInvalidDownloadToken
+	}
+
+	return export, export.FilePath, nil
+}
+
+// attachDownloadLink issues a download token that expires with the link or the archive, whichever is first
+func (s *DataExportService) attachDownloadLink(export *models.DataExport) error {
+	expiresAt := time.Now().Add(s.opts.LinkExpiry)
+	if export.ExpiresAt.Before(expiresAt) {
+		expiresAt = *export.ExpiresAt
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return errors.New("failed to generate token")
+	}
+	token := &models.UserToken{
+		UserID:    export.UserID,
+		Purpose:   models.TokenPurposeDataExport,
+		TokenHash: downloadTokenHash(export.ID, rawToken),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.userTokenRepo.Create(token); err != nil {
+		return errors.New("failed to generate token")
+	}
+
+	export.DownloadURL = fmt.Sprintf("%s/api/v1/auth/me/export/%d/download?token=%s", s.opts.BaseURL, export.ID, rawToken)
+	export.DownloadExpiresAt = &expiresAt
+	return nil
+}
+
+// downloadTokenHash hashes a download token together with the ID of its export, so the token
+// cannot open other exports of the same user
+func downloadTokenHash(exportID uint, rawToken string) string {
+	return utils.HashToken(fmt.Sprintf("%d:%s", exportID, rawToken))
+}
+
+// enqueue hands an export to the worker without blocking the caller
+func (s *DataExportService) enqueue(id uint) {
+	go func() { s.jobs <- id }()
+}
+
+// work processes queued exports one at a time
+func (s *DataExportService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.jobs:
+			s.process(id)
+		}
+	}
+}
+
+// process generates the archive of one export and records the outcome
+func (s *DataExportService) process(id uint) {
+	export, err := s.exportRepo.ClaimPending(id)
+	if err != nil {
+		log.Printf("❌ Failed to claim data export %d: %v", id, err)
+		return
+	}
+	if export == nil {
+		return
+	}
+
+	path, size, err := s.buildArchive(export)
+	if err != nil {
+		log.Printf("❌ Data export %d failed: %v", export.ID, err)
+		export.Status = models.DataExportStatusFailed
+		export.Error = "failed to generate archive"
+		if err := s.exportRepo.Update(export); err != nil {
+			log.Printf("❌ Failed to update data export %d: %v", export.ID, err)
+		}
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.opts.Retention)
+	export.Status = models.DataExportStatusCompleted
+	export.FilePath = path
+	export.FileSize = size
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := s.exportRepo.Update(export); err != nil {
+		log.Printf("❌ Failed to update data export %d: %v", export.ID, err)
+		os.Remove(path)
+		return
+	}
+
+	s.sendExportReadyEmail(export)
+}
+
+// cleanup periodically removes expired archives
+func (s *DataExportService) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(dataExportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.removeExpired()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// removeExpired deletes the archives of expired exports
+func (s *DataExportService) removeExpired() {
+	exports, err := s.exportRepo.FindExpired()
+	if err != nil {
+		log.Printf("❌ Failed to find expired data exports: %v", err)
+		return
+	}
+
+	for i := range exports {
+		export := &exports[i]
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("❌ Failed to remove data export %d: %v", export.ID, err)
+			continue
+		}
+		export.Status = models.DataExportStatusExpired
+		export.FilePath = ""
+		if err := s.exportRepo.Update(export); err != nil {
+			log.Printf("❌ Failed to update data export %d: %v", export.ID, err)
+		}
+	}
+}
+
+// sendExportReadyEmail tells the user that their archive can be downloaded
+func (s *DataExportService) sendExportReadyEmail(export *models.DataExport) {
+	user, err := s.userRepo.FindByID(export.UserID)
+	if err != nil {
+		return
+	}
+	if err := s.attachDownloadLink(export); err != nil {
+		log.Printf("❌ Failed to create download link for data export %d: %v", export.ID, err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe copy of your data you requested is ready. Download it here:\n\n%s\n\nThis link expires at %s. The archive is deleted at %s; you can request a new link until then.\n",
+			user.FullName, export.DownloadURL,
+			export.DownloadExpiresAt.UTC().Format(time.RFC1123), export.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("❌ Failed to send data export email to user %d: %v", user.ID, err)
+	}
+}
+
+// Exported record layouts. Unlike the API models they include soft-delete timestamps.
+type exportedCategory struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Color       string     `json:"color"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type exportedTask struct {
+	ID          uint                `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Status      models.TaskStatus   `json:"status"`
+	Priority    models.TaskPriority `json:"priority"`
+	DueDate     *time.Time          `json:"due_date"`
+	CategoryID  *uint               `json:"category_id"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	DeletedAt   *time.Time          `json:"deleted_at"`
+}
+
+type exportedLogin struct {
+	LoggedInAt time.Time  `json:"logged_in_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+}
+
+// buildArchive collects the user's data and writes it as a zip of JSON and CSV files
+func (s *DataExportService) buildArchive(export *models.DataExport) (string, int64, error) {
+	user, err := s.userRepo.FindByID(export.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	categories, err := s.categoryRepo.FindAllByUserWithDeleted(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	tasks, err := s.taskRepo.FindAllByUserWithDeleted(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	sessions, err := s.sessionRepo.FindAllByUser(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	exportedCategories := make([]exportedCategory, 0, len(categories))
+	categoryRows := make([][]string, 0, len(categories))
+	for _, c := range categories {
+		record := exportedCategory{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Color:       c.Color,
+			CreatedAt:   c.CreatedAt,
+			UpdatedAt:   c.UpdatedAt,
+			DeletedAt:   deletedAt(c.DeletedAt.Time, c.DeletedAt.Valid),
+		}
+		exportedCategories = append(exportedCategories, record)
+		categoryRows = append(categoryRows, []string{
+			strconv.FormatUint(uint64(record.ID), 10), record.Name, record.Description, record.Color,
+			formatTime(&record.CreatedAt), formatTime(&record.UpdatedAt), formatTime(record.DeletedAt),
+		})
+	}
+
+	exportedTasks := make([]exportedTask, 0, len(tasks))
+	taskRows := make([][]string, 0, len(tasks))
+	for _, t := range tasks {
+		record := exportedTask{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			Status:      t.Status,
+			Priority:    t.Priority,
+			DueDate:     t.DueDate,
+			CategoryID:  t.CategoryID,
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+			DeletedAt:   deletedAt(t.DeletedAt.Time, t.DeletedAt.Valid),
+		}
+		categoryID := ""
+		if record.CategoryID != nil {
+			categoryID = strconv.FormatUint(uint64(*record.CategoryID), 10)
+		}
+		exportedTasks = append(exportedTasks, record)
+		taskRows = append(taskRows, []string{
+			strconv.FormatUint(uint64(record.ID), 10), record.Title, record.Description,
+			string(record.Status), string(record.Priority), formatTime(record.DueDate), categoryID,
+			formatTime(&record.CreatedAt), formatTime(&record.UpdatedAt), formatTime(record.DeletedAt),
+		})
+	}
+
+	logins := make([]exportedLogin, 0, len(sessions))
+	loginRows := make([][]string, 0, len(sessions))
+	for _, session := range sessions {
+		record := exportedLogin{
+			LoggedInAt: session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			RevokedAt:  session.RevokedAt,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+		}
+		logins = append(logins, record)
+		loginRows = append(loginRows, []string{
+			formatTime(&record.LoggedInAt), formatTime(&record.LastUsedAt), formatTime(&record.ExpiresAt),
+			formatTime(record.RevokedAt), record.IPAddress, record.UserAgent,
+		})
+	}
+
+	suffix, err := utils.GenerateRandomToken(8)
+	if err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(s.opts.Dir, fmt.Sprintf("export-%d-%s.zip", export.ID, suffix))
+
+	err = writeZip(path, func(zw *zip.Writer) error {
+		if err := writeJSONEntry(zw, "profile.json", user); err != nil {
+			return err
+		}
+		if err := writeJSONEntry(zw, "categories.json", exportedCategories); err != nil {
+			return err
+		}
+		if err := writeCSVEntry(zw, "categories.csv",
+			[]string{"id", "name", "description", "color", "created_at", "updated_at", "deleted_at"}, categoryRows); err != nil {
+			return err
+		}
+		if err := writeJSONEntry(zw, "tasks.json", exportedTasks); err != nil {
+			return err
+		}
+		if err := writeCSVEntry(zw, "tasks.csv",
+			[]string{"id", "title", "description", "status", "priority", "due_date", "category_id", "created_at", "updated_at", "deleted_at"}, taskRows); err != nil {
+			return err
+		}
+		if err := writeJSONEntry(zw, "login_history.json", logins); err != nil {
+			return err
+		}
+		return writeCSVEntry(zw, "login_history.csv",
+			[]string{"logged_in_at", "last_used_at", "expires_at", "revoked_at", "ip_address", "user_agent"}, loginRows)
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+// writeZip writes a zip archive to a temporary file and moves it into place when complete
+func writeZip(path string, fill func(zw *zip.Writer) error) error {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(file)
+	err = fill(zw)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// writeJSONEntry adds an indented JSON file to the archive
+func writeJSONEntry(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeCSVEntry adds a CSV file with a header row to the archive. Cells are escaped so
+// spreadsheets do not run user content as formulas.
+func writeCSVEntry(zw *zip.Writer, name string, header []string, rows [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = escapeCSVFormula(cell)
+		}
+		if err := cw.Write(escaped); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVFormula prefixes cells that a spreadsheet would treat as a formula with a quote
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// deletedAt converts a soft-delete timestamp into a pointer
+func deletedAt(t time.Time, valid bool) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
+
+// formatTime formats an optional timestamp for CSV output
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,44 @@
+package services
+
+import "testing"
+
+func TestDownloadTokenHashIsBoundToExport(t *testing.T) {
+	const token = "c0ffee"
+
+	if downloadTokenHash(1, token) != downloadTokenHash(1, token) {
+		t.Fatal("hash of the same export and token differs")
+	}
+	if downloadTokenHash(1, token) == downloadTokenHash(2, token) {
+		t.Error("a token for export 1 has the same hash for export 2")
+	}
+	if downloadTokenHash(1, token) == downloadTokenHash(1, token+"0") {
+		t.Error("different tokens have the same hash")
+	}
+	// IDs and tokens are separated, so "1" + "2..." cannot pass for "12" + "..."
+	if downloadTokenHash(1, "2"+token) == downloadTokenHash(12, token) {
+		t.Error("export ID and token run into each other")
+	}
+}
+
+func TestEscapeCSVFormula(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"", ""},
+		{"Plain title", "Plain title"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+		{"é=1", "é=1"},
+	}
+	for _, tt := range tests {
+		if got := escapeCSVFormula(tt.cell); got != tt.want {
+			t.Errorf("escapeCSVFormula(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	userRepo         *repository.UserRepository
+	workspaceRepo    *repository.WorkspaceRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo *repository.NotificationRepository, userRepo *repository.UserRepository, workspaceRepo *repository.WorkspaceRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		workspaceRepo:    workspaceRepo,
+	}
+}
+
+// NotifyMentions notifies the users mentioned with @username in a task description or comment.
+// Only mentions that are new compared to the previous text count, and only users who can see
+// the task are notified. Failures are logged and never fail the change that caused them.
+func (s *NotificationService) NotifyMentions(actorID uint, task *models.Task, commentID *uint, previousText, text string) {
+	previous := make(map[string]bool)
+	for _, username := range utils.ParseMentions(previousText) {
+		previous[username] = true
+	}
+	var usernames []string
+	for _, username := range utils.ParseMentions(text) {
+		if !previous[username] {
+			usernames = append(usernames, username)
+		}
+	}
+	if len(usernames) == 0 {
+		return
+	}
+
+	users, err := s.userRepo.FindByUsernames(usernames)
+	if err != nil {
+		log.Printf("❌ Failed to look up mentioned users for task %d: %v", task.ID, err)
+		return
+	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		log.Printf("❌ Failed to look up user %d for mention notifications: %v", actorID, err)
+		return
+	}
+
+	message := fmt.Sprintf("%s mentioned you in \"%s\"", actor.Username, task.Title)
+	if commentID != nil {
+		message = fmt.Sprintf("%s mentioned you in a comment on \"%s\"", actor.Username, task.Title)
+	}
+	if runes := []rune(message); len(runes) > 255 {
+		message = string(runes[:252]) + "..."
+	}
+
+	var notifications []models.Notification
+	for _, user := range users {
+		if user.ID == actorID || !s.canSeeTask(user.ID, task) {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:    user.ID,
+			Type:      models.NotificationTypeMention,
+			ActorID:   &actorID,
+			TaskID:    &task.ID,
+			CommentID: commentID,
+			Message:   message,
+		})
+	}
+
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		log.Printf("❌ Failed to create mention notifications for task %d: %v", task.ID, err)
+	}
+}
+
+// GetAll retrieves the notifications of a user with filtering and pagination
+func (s *NotificationService) GetAll(userID uint, filter models.NotificationFilter) ([]models.Notification, int64, error) {
+	filter.SetDefaults()
+	return s.notificationRepo.FindAllByUser(userID, filter)
+}
+
+// GetUnreadCount counts the unread notifications of a user
+func (s *NotificationService) GetUnreadCount(userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead marks a notification as read
+func (s *NotificationService) MarkRead(id uint, userID uint) (*models.Notification, error) {
+	if _, err := s.notificationRepo.FindByID(id, userID); err != nil {
+		return nil, utils.ErrNotificationNotFound
+	}
+
+	if err := s.notificationRepo.MarkRead(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.notificationRepo.FindByID(id, userID)
+}
+
+// MarkAllRead marks all unread notifications of a user as read and returns how many changed
+func (s *NotificationService) MarkAllRead(userID uint, notificationType string) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID, notificationType)
+}
+
+// canSeeTask reports whether a user can see a task: its creator for personal tasks,
+// members for workspace tasks
+func (s *NotificationService) canSeeTask(userID uint, task *models.Task) bool {
+	if task.WorkspaceID == nil {
+		return task.UserID == userID
+	}
+
+	isMember, err := s.workspaceRepo.IsMember(*task.WorkspaceID, userID)
+	if err != nil {
+		log.Printf("❌ Failed to check workspace membership of user %d: %v", userID, err)
+		return false
+	}
+	return isMember
+}
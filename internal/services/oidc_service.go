@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/oidc"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// oidcStateExpiry is how long a user has to complete the login at the provider
+const oidcStateExpiry = 10 * time.Minute
+
+// usernameInvalidChars matches characters not allowed in generated usernames
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type OIDCService struct {
+	provider      *oidc.Provider
+	oidcRepo      *repository.OIDCRepository
+	userRepo      *repository.UserRepository
+	sessionRepo   *repository.SessionRepository
+	apiKeyRepo    *repository.APIKeyRepository
+	authService   *AuthService
+	autoProvision bool
+}
+
+// NewOIDCService creates a new OIDC service. A nil provider disables single sign-on.
+func NewOIDCService(
+	provider *oidc.Provider,
+	oidcRepo *repository.OIDCRepository,
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	apiKeyRepo *repository.APIKeyRepository,
+	authService *AuthService,
+	autoProvision bool,
+) *OIDCService {
+	return &OIDCService{
+		provider:      provider,
+		oidcRepo:      oidcRepo,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		apiKeyRepo:    apiKeyRepo,
+		authService:   authService,
+		autoProvision: autoProvision,
+	}
+}
+
+// StartLogin creates a pending login and returns the provider URL to redirect to
+// together with the state value that must come back to the callback
+func (s *OIDCService) StartLogin(ctx context.Context) (string, string, error) {
+	if s.provider == nil {
+		return "", "", utils.ErrOIDCNotConfigured
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		log.Printf("❌ OIDC login could not be started: %v", err)
+		return "", "", utils.ErrOIDCUnavailable
+	}
+
+	// Opportunistic cleanup of abandoned logins
+	if err := s.oidcRepo.DeleteExpiredStates(); err != nil {
+		log.Printf("❌ Failed to delete expired OIDC states: %v", err)
+	}
+
+	if err := s.oidcRepo.CreateState(&models.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oidcStateExpiry),
+	}); err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// HandleCallback exchanges the authorization code, verifies the ID token and logs the
+// matching user in, linking or creating the account by verified email when needed
+func (s *OIDCService) HandleCallback(ctx context.Context, state, code string, client models.ClientInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	if s.provider == nil {
+		return nil, nil, utils.ErrOIDCNotConfigured
+	}
+
+	pending, err := s.oidcRepo.ConsumeState(utils.HashToken(state))
+	if err != nil {
+		return nil, nil, utils.ErrInvalidOIDCState
+	}
+
+	tokens, err := s.provider.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		log.Printf("❌ OIDC code exchange failed: %v", err)
+		return nil, nil, utils.ErrOIDCLoginFailed
+	}
+
+	claims, err := s.provider.VerifyIDToken(ctx, tokens.IDToken, pending.Nonce)
+	if err != nil {
+		log.Printf("❌ OIDC id token rejected: %v", err)
+		return nil, nil, utils.ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveUser(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.authService.LoginUser(user, client)
+}
+
+// resolveUser finds the user for the ID token: by linked identity first,
+// then by verified email, and finally by creating a new account
+func (s *OIDCService) resolveUser(claims *oidc.IDTokenClaims) (*models.User, error) {
+	issuer := claims.Issuer
+	email := strings.TrimSpace(strings.ToLower(claims.Email))
+
+	identity, err := s.oidcRepo.FindIdentity(issuer, claims.Subject)
+	if err == nil && identity.User.ID != 0 {
+		if err := s.oidcRepo.TouchIdentity(identity.ID, email); err != nil {
+			return nil, err
+		}
+		return &identity.User, nil
+	}
+
+	// Only a verified email may be used to match or create an account
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, utils.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err == nil {
+		if err := s.linkExistingUser(user); err != nil {
+			return nil, err
+		}
+	} else {
+		if !s.autoProvision {
+			return nil, utils.ErrOIDCSignupDisabled
+		}
+		if user, err = s.provisionUser(claims, email); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if err := s.oidcRepo.CreateIdentity(&models.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      issuer,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// linkExistingUser prepares a local account for its first single sign-on login.
+// If the local email was never verified, whoever registered it may not own the address,
+// so their password, sessions and API keys are discarded before the provider's user takes over.
+func (s *OIDCService) linkExistingUser(user *models.User) error {
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	randomPassword, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// The provider has verified the address
+	now := time.Now()
+	if _, err := s.userRepo.MarkEmailVerified(user.ID, user.Email, now); err != nil {
+		return err
+	}
+	user.EmailVerifiedAt = &now
+	if err := s.authService.setPassword(user, randomPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.RevokeAllByUser(user.ID); err != nil {
+		return err
+	}
+	if _, err := s.apiKeyRepo.RevokeAllByUser(user.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// provisionUser creates a new account for a provider user. The account gets an unusable
+// random password; a local password can be set later through the password reset flow.
+func (s *OIDCService) provisionUser(claims *oidc.IDTokenClaims, email string) (*models.User, error) {
+	username, err := s.uniqueUsername(claims.PreferredUsername, email)
+	if err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := strings.TrimSpace(claims.Name)
+	if fullName == "" {
+		fullName = username
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Username:        username,
+		Email:           email,
+		Password:        randomPassword,
+		FullName:        truncate(fullName, 100),
+		Role:            models.UserRoleUser,
+		EmailVerifiedAt: &now,
+	}
+	if err := user.HashPassword(); err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	return user, nil
+}
+
+// uniqueUsername derives an unused username from the preferred username or the email address
+func (s *OIDCService) uniqueUsername(preferred, email string) (string, error) {
+	base := preferred
+	if base == "" {
+		base = strings.SplitN(email, "@", 2)[0]
+	}
+	base = truncate(usernameInvalidChars.ReplaceAllString(base, ""), 40)
+	if len(base) < 3 {
+		base = "user" + base
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = base + strconv.Itoa(i)
+		}
+
+		exists, err := s.userRepo.ExistsByUsername(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+
+	suffix, err := utils.GenerateRandomToken(3)
+	if err != nil {
+		return "", err
+	}
+	return base + "-" + suffix, nil
+}
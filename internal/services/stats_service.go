@@ -1,10 +1,16 @@
 package services
 
 import (
+	"time"
+
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
 	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
 
+// maxTimeReportDays limits the range of a time report
+const maxTimeReportDays = 366
+
 type StatsService struct {
 	statsRepo *repository.StatsRepository
 }
@@ -29,6 +35,51 @@ func (s *StatsService) GetUpcomingTasks(userID uint, days int) ([]models.Task, e
 	return s.statsRepo.GetUpcomingTasks(userID, days)
 }
 
+// GetTimeReport sums up the tracked time on the tasks visible to the user by day or category.
+// The range defaults to the last 30 days; days are UTC dates.
+func (s *StatsService) GetTimeReport(userID uint, filter models.TimeReportFilter) (*models.TimeReport, error) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if filter.To != "" {
+		parsed, err := time.Parse(time.DateOnly, filter.To)
+		if err != nil {
+			return nil, utils.ErrInvalidReportRange
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if filter.From != "" {
+		parsed, err := time.Parse(time.DateOnly, filter.From)
+		if err != nil {
+			return nil, utils.ErrInvalidReportRange
+		}
+		from = parsed
+	}
+	if from.After(to) || to.Sub(from) >= maxTimeReportDays*24*time.Hour {
+		return nil, utils.ErrInvalidReportRange
+	}
+
+	groupBy := models.TimeReportGroup(filter.GroupBy)
+	if groupBy == "" {
+		groupBy = models.TimeReportGroupDay
+	}
+
+	rows, err := s.statsRepo.GetTimeReport(userID, from, to.AddDate(0, 0, 1), groupBy, filter.WorkspaceID, filter.Mine)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TimeReport{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		GroupBy: groupBy,
+		Rows:    rows,
+	}
+	for _, row := range rows {
+		report.TotalSeconds += row.TotalSeconds
+	}
+	return report, nil
+}
+
 // GetOverdueTasks retrieves overdue tasks
 func (s *StatsService) GetOverdueTasks(userID uint) ([]models.Task, error) {
 	return s.statsRepo.GetOverdueTasks(userID)
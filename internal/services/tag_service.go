@@ -0,0 +1,133 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type TagService struct {
+	tagRepo       *repository.TagRepository
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo *repository.TagRepository, workspaceRepo *repository.WorkspaceRepository) *TagService {
+	return &TagService{
+		tagRepo:       tagRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// Create creates a new personal tag, or a tag of a workspace the user can edit
+func (s *TagService) Create(req *models.CreateTagRequest, userID uint) (*models.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, utils.ErrTagNameRequired
+	}
+
+	workspaceID := req.WorkspaceID
+	if workspaceID != nil && *workspaceID == 0 {
+		workspaceID = nil
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.tagRepo.ExistsByName(name, userID, workspaceID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, utils.ErrTagNameTaken
+	}
+
+	tag := &models.Tag{
+		Name:        name,
+		Color:       strings.TrimSpace(req.Color),
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+	}
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetByID retrieves a tag by ID for a specific user
+func (s *TagService) GetByID(id uint, userID uint) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// GetAllByUser retrieves all tags visible to a user with pagination.
+// A non-zero workspaceID limits the result to that workspace.
+func (s *TagService) GetAllByUser(userID uint, workspaceID uint, page, pageSize int) ([]models.Tag, int64, error) {
+	// Set defaults
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	if workspaceID > 0 {
+		isMember, err := s.workspaceRepo.IsMember(workspaceID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !isMember {
+			return nil, 0, utils.ErrWorkspaceNotFound
+		}
+	}
+
+	return s.tagRepo.FindAllByUser(userID, workspaceID, page, pageSize)
+}
+
+// Update renames or recolors a tag
+func (s *TagService) Update(id uint, req *models.UpdateTagRequest, userID uint) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTagNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, tag.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		exists, err := s.tagRepo.ExistsByName(name, userID, tag.WorkspaceID, tag.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, utils.ErrTagNameTaken
+		}
+		tag.Name = name
+	}
+	if req.Color != "" {
+		tag.Color = strings.TrimSpace(req.Color)
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// Delete deletes a tag and removes it from its tasks
+func (s *TagService) Delete(id uint, userID uint) error {
+	tag, err := s.tagRepo.FindByID(id, userID)
+	if err != nil {
+		return utils.ErrTagNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, tag.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	return s.tagRepo.Delete(tag.ID, userID)
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"errors"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hoanghnt/TaskManagementAPI/internal/models"
@@ -9,29 +12,76 @@ import (
 	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
 )
 
+// TaskOptions holds subtask and dependency settings
+type TaskOptions struct {
+	MaxDepth             int                    // levels of nesting including the top-level task
+	CompleteParent       models.SubtaskBehavior // what happens to open subtasks when their parent is completed
+	DeleteParent         models.SubtaskBehavior // what happens to subtasks when their parent is deleted
+	BlockersPreventStart bool                   // open blockers also prevent moving a task to in_progress
+}
+
 type TaskService struct {
-	taskRepo     *repository.TaskRepository
-	categoryRepo *repository.CategoryRepository
+	taskRepo            *repository.TaskRepository
+	categoryRepo        *repository.CategoryRepository
+	tagRepo             *repository.TagRepository
+	workspaceRepo       *repository.WorkspaceRepository
+	workflowRepo        *repository.WorkflowRepository
+	notificationService *NotificationService
+	activityService     *ActivityService
+	opts                TaskOptions
 }
 
 // NewTaskService creates a new task service
-func NewTaskService(taskRepo *repository.TaskRepository, categoryRepo *repository.CategoryRepository) *TaskService {
+func NewTaskService(taskRepo *repository.TaskRepository, categoryRepo *repository.CategoryRepository, tagRepo *repository.TagRepository, workspaceRepo *repository.WorkspaceRepository, workflowRepo *repository.WorkflowRepository, notificationService *NotificationService, activityService *ActivityService, opts TaskOptions) *TaskService {
 	return &TaskService{
-		taskRepo:     taskRepo,
-		categoryRepo: categoryRepo,
+		taskRepo:            taskRepo,
+		categoryRepo:        categoryRepo,
+		tagRepo:             tagRepo,
+		workspaceRepo:       workspaceRepo,
+		workflowRepo:        workflowRepo,
+		notificationService: notificationService,
+		activityService:     activityService,
+		opts:                opts,
 	}
 }
 
-// CreateTask creates a new task
-func (s *TaskService) CreateTask(userID uint, req models.CreateTaskRequest) (*models.Task, error) {
-	// Validate category if provided
-	if req.CategoryID != nil {
-		exists, err := s.categoryRepo.ExistsByID(*req.CategoryID, userID)
+// CreateTask creates a new task, personal or in a workspace the user can edit. Subtasks are
+// created in the workspace of their parent. requestID ties the recorded activity to the HTTP request.
+func (s *TaskService) CreateTask(userID uint, req models.CreateTaskRequest, requestID string) (*models.Task, error) {
+	workspaceID := req.WorkspaceID
+	if workspaceID != nil && *workspaceID == 0 {
+		workspaceID = nil
+	}
+
+	// Validate parent if provided
+	parentID := req.ParentID
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if parentID != nil {
+		parent, err := s.taskRepo.FindByID(*parentID, userID)
 		if err != nil {
+			return nil, utils.ErrParentTaskNotFound
+		}
+		if req.WorkspaceID == nil {
+			workspaceID = parent.WorkspaceID
+		}
+		if !sameWorkspace(parent.WorkspaceID, workspaceID) {
+			return nil, utils.ErrParentWorkspaceMismatch
+		}
+		if err := s.validateDepth(parent.ID, 1); err != nil {
 			return nil, err
 		}
-		if !exists {
-			return nil, utils.ErrCategoryNotFound
+	}
+
+	if err := requireWorkspaceEditor(s.workspaceRepo, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	// Validate category if provided
+	if req.CategoryID != nil {
+		if err := s.validateCategory(*req.CategoryID, userID, workspaceID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -40,12 +90,54 @@ func (s *TaskService) CreateTask(userID uint, req models.CreateTaskRequest) (*mo
 		return nil, errors.New("due date cannot be in the past")
 	}
 
-	// Set default values if not provided
-	status := req.Status
-	if status == "" {
-		status = models.TaskStatusPending
+	// A recurrence rule starts a series with the task as first occurrence
+	var series *models.TaskSeries
+	if req.RecurrenceRule != "" {
+		if req.DueDate == nil {
+			return nil, utils.ErrRecurrenceNeedsDueDate
+		}
+		rule, err := utils.ParseRRule(req.RecurrenceRule)
+		if err != nil {
+			return nil, err
+		}
+		series = &models.TaskSeries{
+			Rule:        rule.String(),
+			StartAt:     *req.DueDate,
+			UserID:      userID,
+			WorkspaceID: workspaceID,
+		}
+	}
+
+	// Validate assignees if provided
+	assigneeIDs := uniqueIDs(req.AssigneeIDs)
+	if err := s.validateAssignees(assigneeIDs, userID, workspaceID); err != nil {
+		return nil, err
+	}
+	assignees := make([]models.TaskAssignee, 0, len(assigneeIDs))
+	for _, assigneeID := range assigneeIDs {
+		assignees = append(assignees, models.TaskAssignee{UserID: assigneeID, AssignedByID: userID})
+	}
+
+	// Tags are looked up by name in the task's workspace
+	tags, err := s.resolveTags(req.Tags, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The status comes from the workflow of the task's workspace; new tasks start in the
+	// first todo status unless another one is given
+	workflow, err := effectiveWorkflow(s.workflowRepo, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := workflow.FirstStatus(models.StatusCategoryTodo)
+	if req.Status != "" {
+		if status = workflow.Status(req.Status); status == nil {
+			return nil, utils.ErrUnknownStatus
+		}
 	}
 
+	// Set default values if not provided
 	priority := req.Priority
 	if priority == "" {
 		priority = models.TaskPriorityMedium
@@ -55,35 +147,107 @@ func (s *TaskService) CreateTask(userID uint, req models.CreateTaskRequest) (*mo
 	task := &models.Task{
 		Title:       req.Title,
 		Description: req.Description,
-		Status:      status,
+		Status:      status.Category.TaskStatus(),
 		Priority:    priority,
 		DueDate:     req.DueDate,
 		UserID:      userID,
+		WorkspaceID: workspaceID,
+		ParentID:    parentID,
+		Series:      series,
 		CategoryID:  req.CategoryID,
+		Assignees:   assignees,
+		Tags:        tags,
+
+		WorkflowStatus: status.Key,
+	}
+	if req.EstimateMinutes != nil {
+		task.EstimateMinutes = positiveOrNil(*req.EstimateMinutes)
+	}
+	if req.StoryPoints != nil {
+		task.StoryPoints = positiveOrNil(*req.StoryPoints)
 	}
 
 	if err := s.taskRepo.Create(task); err != nil {
 		return nil, err
 	}
 
+	s.activityService.RecordTask(models.ActivityActionCreated, nil, task, userID, requestID)
+	s.notificationService.NotifyMentions(userID, task, nil, "", task.Description)
+
 	// Reload with relationships
 	return s.taskRepo.FindByID(task.ID, userID)
 }
 
-// GetTaskByID retrieves a task by ID
-func (s *TaskService) GetTaskByID(id uint, userID uint) (*models.Task, error) {
+// GetTaskByID retrieves a task by ID with its dependencies and tracked time, optionally with the progress of its subtasks
+func (s *TaskService) GetTaskByID(id uint, userID uint, query models.TaskQuery) (*models.Task, error) {
 	task, err := s.taskRepo.FindByID(id, userID)
 	if err != nil {
 		return nil, utils.ErrTaskNotFound
 	}
+
+	if task.Dependencies, err = s.taskRepo.FindDependencies(task.ID); err != nil {
+		return nil, err
+	}
+	if task.TimeSpent, err = s.taskRepo.FindTimeSpent(task.ID); err != nil {
+		return nil, err
+	}
+
+	if query.IncludeProgress {
+		tasks := []models.Task{*task}
+		if err := s.taskRepo.AttachProgress(tasks); err != nil {
+			return nil, err
+		}
+		return &tasks[0], nil
+	}
 	return task, nil
 }
 
+// GetSubtasks retrieves the direct subtasks of a task, oldest first
+func (s *TaskService) GetSubtasks(id uint, userID uint, query models.TaskQuery) ([]models.Task, error) {
+	exists, err := s.taskRepo.ExistsByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrTaskNotFound
+	}
+
+	tasks, err := s.taskRepo.FindSubtasks(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.IncludeProgress {
+		if err := s.taskRepo.AttachProgress(tasks); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
 // GetAllTasks retrieves all tasks with filtering and pagination
 func (s *TaskService) GetAllTasks(userID uint, filter models.TaskFilter) ([]models.Task, int64, error) {
 	// Set default values for pagination
 	filter.SetDefaults()
 
+	// Validate workspace if provided in filter
+	if filter.WorkspaceID > 0 {
+		isMember, err := s.workspaceRepo.IsMember(filter.WorkspaceID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !isMember {
+			return nil, 0, utils.ErrWorkspaceNotFound
+		}
+	}
+
+	// Validate assignee filter
+	if filter.Assignee != "" && filter.Assignee != "me" && filter.Assignee != "none" {
+		if _, err := strconv.ParseUint(filter.Assignee, 10, 32); err != nil {
+			return nil, 0, utils.ErrInvalidAssigneeFilter
+		}
+	}
+
 	// Validate category if provided in filter
 	if filter.CategoryID > 0 {
 		exists, err := s.categoryRepo.ExistsByID(filter.CategoryID, userID)
@@ -95,28 +259,52 @@ func (s *TaskService) GetAllTasks(userID uint, filter models.TaskFilter) ([]mode
 		}
 	}
 
-	return s.taskRepo.FindAllByUser(userID, filter)
+	tasks, total, err := s.taskRepo.FindAllByUser(userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filter.IncludeProgress {
+		if err := s.taskRepo.AttachProgress(tasks); err != nil {
+			return nil, 0, err
+		}
+	}
+	return tasks, total, nil
+}
+
+// GetTaskHistory retrieves the activity history of a task, newest first
+func (s *TaskService) GetTaskHistory(id uint, userID uint, filter models.ActivityFilter) ([]models.Activity, int64, error) {
+	exists, err := s.taskRepo.ExistsByID(id, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, utils.ErrTaskNotFound
+	}
+
+	return s.activityService.GetHistory(models.ActivityEntityTask, id, filter)
 }
 
 // UpdateTask updates an existing task
-func (s *TaskService) UpdateTask(id uint, userID uint, req models.UpdateTaskRequest) (*models.Task, error) {
+func (s *TaskService) UpdateTask(id uint, userID uint, req models.UpdateTaskRequest, requestID string) (*models.Task, error) {
 	// Check if task exists
 	task, err := s.taskRepo.FindByID(id, userID)
 	if err != nil {
 		return nil, utils.ErrTaskNotFound
 	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	before := *task
 
 	// Validate category if being updated
 	if req.CategoryID != nil {
 		// Allow null category (set to 0 to remove category)
 		if *req.CategoryID > 0 {
-			exists, err := s.categoryRepo.ExistsByID(*req.CategoryID, userID)
-			if err != nil {
+			if err := s.validateCategory(*req.CategoryID, userID, task.WorkspaceID); err != nil {
 				return nil, err
 			}
-			if !exists {
-				return nil, utils.ErrCategoryNotFound
-			}
 		}
 		task.CategoryID = req.CategoryID
 	}
@@ -126,6 +314,52 @@ func (s *TaskService) UpdateTask(id uint, userID uint, req models.UpdateTaskRequ
 		return nil, errors.New("due date cannot be in the past")
 	}
 
+	// Validate parent if the task is being moved (set to 0 to make it a top-level task)
+	if req.ParentID != nil {
+		if *req.ParentID > 0 {
+			if err := s.validateMove(task, *req.ParentID, userID); err != nil {
+				return nil, err
+			}
+			task.ParentID = req.ParentID
+		} else {
+			task.ParentID = nil
+		}
+	}
+
+	// Resolve the new tags if they are being replaced
+	var tags []models.Tag
+	if req.Tags != nil {
+		if tags, err = s.resolveTags(req.Tags, userID, task.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The workflow decides which status changes are allowed
+	status := before.Status
+	if req.Status != "" {
+		workflowStatus, err := s.resolveStatus(task, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		status = workflowStatus.Category.TaskStatus()
+		task.WorkflowStatus = workflowStatus.Key
+	}
+
+	// Open blockers prevent some status changes
+	if status != before.Status {
+		if err := s.checkNotBlocked(task.ID, status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Completing a task applies the subtask behavior
+	completed := status == models.TaskStatusCompleted && before.Status != models.TaskStatusCompleted
+	if completed {
+		if err := s.checkCanComplete(task.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields only if provided (partial update)
 	if req.Title != "" {
 		task.Title = req.Title
@@ -133,9 +367,7 @@ func (s *TaskService) UpdateTask(id uint, userID uint, req models.UpdateTaskRequ
 	if req.Description != "" {
 		task.Description = req.Description
 	}
-	if req.Status != "" {
-		task.Status = req.Status
-	}
+	task.Status = status
 	if req.Priority != "" {
 		task.Priority = req.Priority
 	}
@@ -143,62 +375,269 @@ func (s *TaskService) UpdateTask(id uint, userID uint, req models.UpdateTaskRequ
 		task.DueDate = req.DueDate
 	}
 
+	// Effort (set the estimate or story points to 0 to remove them)
+	if req.EstimateMinutes != nil {
+		task.EstimateMinutes = positiveOrNil(*req.EstimateMinutes)
+	}
+	if req.StoryPoints != nil {
+		task.StoryPoints = positiveOrNil(*req.StoryPoints)
+	}
+	if req.RemainingMinutes != nil {
+		task.RemainingMinutes = req.RemainingMinutes
+	}
+
 	// Save updates
 	if err := s.taskRepo.Update(task); err != nil {
 		return nil, err
 	}
 
+	s.activityService.RecordTask(models.ActivityActionUpdated, &before, task, userID, requestID)
+	s.notificationService.NotifyMentions(userID, task, nil, before.Description, task.Description)
+
+	if req.Tags != nil {
+		if err := s.taskRepo.ReplaceTags(task, tags); err != nil {
+			return nil, err
+		}
+		added, removed := diffTags(before.Tags, tags)
+		s.activityService.RecordTaskTags(task, added, removed, userID, requestID)
+	}
+
+	if completed {
+		if err := s.completeSubtasks([]uint{task.ID}, userID, requestID); err != nil {
+			return nil, err
+		}
+		if err := s.createNextOccurrences([]models.Task{*task}, userID, requestID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Reload with relationships
 	return s.taskRepo.FindByID(task.ID, userID)
 }
 
-// UpdateTaskStatus updates only the status of a task
-func (s *TaskService) UpdateTaskStatus(id uint, userID uint, status models.TaskStatus) (*models.Task, error) {
-	// Check if task exists
-	exists, err := s.taskRepo.ExistsByID(id, userID)
+// UpdateTaskStatus moves a task to a status of its workflow
+func (s *TaskService) UpdateTaskStatus(id uint, userID uint, key string, requestID string) (*models.Task, error) {
+	// Check if task exists and may be changed by the user
+	task, err := s.taskRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	// The workflow decides which status changes are allowed
+	workflowStatus, err := s.resolveStatus(task, key)
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, utils.ErrTaskNotFound
+	status := workflowStatus.Category.TaskStatus()
+
+	// Open blockers prevent some status changes
+	if status != task.Status {
+		if err := s.checkNotBlocked(task.ID, status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Completing a task applies the subtask behavior
+	completed := status == models.TaskStatusCompleted && task.Status != models.TaskStatusCompleted
+	if completed {
+		if err := s.checkCanComplete(task.ID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update status
-	if err := s.taskRepo.UpdateStatus(id, userID, status); err != nil {
+	if err := s.taskRepo.UpdateStatus(id, userID, status, workflowStatus.Key); err != nil {
 		return nil, err
 	}
 
+	after := *task
+	after.Status = status
+	after.WorkflowStatus = workflowStatus.Key
+	s.activityService.RecordTask(models.ActivityActionStatusChanged, task, &after, userID, requestID)
+
+	if completed {
+		if err := s.completeSubtasks([]uint{task.ID}, userID, requestID); err != nil {
+			return nil, err
+		}
+		if err := s.createNextOccurrences([]models.Task{after}, userID, requestID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Return updated task
 	return s.taskRepo.FindByID(id, userID)
 }
 
-// DeleteTask deletes a task
-func (s *TaskService) DeleteTask(id uint, userID uint) error {
-	// Check if task exists
-	exists, err := s.taskRepo.ExistsByID(id, userID)
+// DeleteTask deletes a task and blocks, deletes or detaches its subtasks as configured
+func (s *TaskService) DeleteTask(id uint, userID uint, requestID string) error {
+	// Check if task exists and may be changed by the user
+	task, err := s.taskRepo.FindByID(id, userID)
 	if err != nil {
+		return utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
 		return err
 	}
-	if !exists {
-		return utils.ErrTaskNotFound
+
+	if err := s.releaseSubtasks(task.ID, userID, requestID); err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.Delete(id, userID); err != nil {
+		return err
+	}
+
+	s.activityService.RecordTask(models.ActivityActionDeleted, task, nil, userID, requestID)
+	return nil
+}
+
+// AssignTask assigns users to a task. Every assignee must be able to see the task.
+func (s *TaskService) AssignTask(id uint, userID uint, req models.AssignTaskRequest, requestID string) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	assigneeIDs := uniqueIDs(req.UserIDs)
+	if err := s.validateAssignees(assigneeIDs, task.UserID, task.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.AddAssignees(task.ID, assigneeIDs, userID); err != nil {
+		return nil, err
+	}
+
+	// Only record users that were not assigned before
+	assigned := make(map[uint]bool, len(task.Assignees))
+	for _, assignee := range task.Assignees {
+		assigned[assignee.UserID] = true
+	}
+	var added []uint
+	for _, assigneeID := range assigneeIDs {
+		if !assigned[assigneeID] {
+			added = append(added, assigneeID)
+		}
+	}
+	s.activityService.RecordTaskAssignees(task, added, nil, userID, requestID)
+
+	return s.taskRepo.FindByID(task.ID, userID)
+}
+
+// UnassignTask removes an assignee from a task. Assignees may always unassign themselves.
+func (s *TaskService) UnassignTask(id uint, userID uint, assigneeID uint, requestID string) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if assigneeID != userID {
+		if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.taskRepo.RemoveAssignee(task.ID, assigneeID); err != nil {
+		if err.Error() == "assignee not found" {
+			return nil, utils.ErrAssigneeNotFound
+		}
+		return nil, err
 	}
 
-	return s.taskRepo.Delete(id, userID)
+	s.activityService.RecordTaskAssignees(task, nil, []uint{assigneeID}, userID, requestID)
+	return s.taskRepo.FindByID(task.ID, userID)
 }
 
-// BulkUpdateStatus updates status for multiple tasks
-func (s *TaskService) BulkUpdateStatus(userID uint, req models.BulkUpdateStatusRequest) (*models.BulkUpdateResponse, error) {
+// BulkUpdateStatus moves multiple tasks to a status. The status must exist in the workflows of all
+// editable tasks with the same category, and their workflows must allow the move; otherwise nothing
+// changes. Tasks with open blockers and, when completing parents is blocked, tasks with open subtasks
+// are skipped unless those are completed in the same request.
+func (s *TaskService) BulkUpdateStatus(userID uint, req models.BulkUpdateStatusRequest, requestID string) (*models.BulkUpdateResponse, error) {
 	// Validate task IDs not empty
 	if len(req.TaskIDs) == 0 {
 		return nil, errors.New("task IDs cannot be empty")
 	}
 
-	// Update status for all tasks
-	rowsAffected, err := s.taskRepo.BulkUpdateStatus(req.TaskIDs, userID, req.Status)
+	// Remember the previous status of the tasks that will change for the activity log
+	tasks, err := s.taskRepo.FindEditableByIDs(req.TaskIDs, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Check the move against the workflow of every task before changing any of them
+	var status models.TaskStatus
+	workflows := newWorkflowCache(s.workflowRepo)
+	for i := range tasks {
+		workflow, err := workflows.forTask(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		workflowStatus, err := transitionTo(workflow, &tasks[i], req.Status)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && workflowStatus.Category.TaskStatus() != status {
+			return nil, utils.ErrStatusCategoryMismatch
+		}
+		status = workflowStatus.Category.TaskStatus()
+	}
+
+	// Skipping a task can block another one, so repeat until nothing is skipped
+	for count := -1; count != len(tasks); {
+		count = len(tasks)
+		if status == models.TaskStatusCompleted && s.opts.CompleteParent == models.SubtaskBehaviorBlock {
+			if tasks, err = s.withoutOpenSubtasks(tasks); err != nil {
+				return nil, err
+			}
+		}
+		if tasks, err = s.withoutOpenBlockers(tasks, status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update status for the remaining tasks; tasks the user cannot edit count as failed
+	var rowsAffected int64
+	if len(tasks) > 0 {
+		taskIDs := make([]uint, len(tasks))
+		for i := range tasks {
+			taskIDs[i] = tasks[i].ID
+		}
+		if rowsAffected, err = s.taskRepo.BulkUpdateStatus(taskIDs, userID, status, req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	var activities []models.Activity
+	var completed []models.Task
+	var completedIDs []uint
+	for i := range tasks {
+		if tasks[i].WorkflowStatus == req.Status {
+			continue
+		}
+		after := tasks[i]
+		after.Status = status
+		after.WorkflowStatus = req.Status
+		activities = append(activities, taskActivity(models.ActivityActionStatusChanged, &tasks[i], &after, userID, requestID))
+		if status == models.TaskStatusCompleted && tasks[i].Status != models.TaskStatusCompleted {
+			completed = append(completed, after)
+			completedIDs = append(completedIDs, after.ID)
+		}
+	}
+	s.activityService.Record(activities...)
+
+	if len(completed) > 0 {
+		if err := s.completeSubtasks(completedIDs, userID, requestID); err != nil {
+			return nil, err
+		}
+		if err := s.createNextOccurrences(completed, userID, requestID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Calculate success/failed counts
 	totalCount := len(req.TaskIDs)
 	successCount := int(rowsAffected)
@@ -213,3 +652,706 @@ func (s *TaskService) BulkUpdateStatus(userID uint, req models.BulkUpdateStatusR
 
 	return response, nil
 }
+
+// resolveStatus finds the status of a task's workflow with the key and checks that the workflow
+// allows the task to move there
+func (s *TaskService) resolveStatus(task *models.Task, key string) (*models.WorkflowStatus, error) {
+	workflow, err := effectiveWorkflow(s.workflowRepo, task.WorkspaceID, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return transitionTo(workflow, task, key)
+}
+
+// validateCategory checks that a category is visible to the user and belongs to the same
+// workspace as the task (or is personal for a personal task)
+func (s *TaskService) validateCategory(categoryID uint, userID uint, workspaceID *uint) error {
+	category, err := s.categoryRepo.FindByID(categoryID, userID)
+	if err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	if !sameWorkspace(category.WorkspaceID, workspaceID) {
+		return utils.ErrCategoryWorkspaceMismatch
+	}
+	return nil
+}
+
+// sameWorkspace compares optional workspace IDs
+func sameWorkspace(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// validateAssignees checks that every assignee can see a task of the given creator and workspace:
+// personal tasks can only be assigned to their creator, workspace tasks to members
+func (s *TaskService) validateAssignees(assigneeIDs []uint, creatorID uint, workspaceID *uint) error {
+	for _, assigneeID := range assigneeIDs {
+		if workspaceID == nil {
+			if assigneeID != creatorID {
+				return utils.ErrAssigneeNoAccess
+			}
+			continue
+		}
+
+		isMember, err := s.workspaceRepo.IsMember(*workspaceID, assigneeID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return utils.ErrAssigneeNoAccess
+		}
+	}
+	return nil
+}
+
+// resolveTags finds the tags with the given names in a workspace, or among the user's personal
+// tags when workspaceID is nil. Names match regardless of case; missing tags are created.
+func (s *TaskService) resolveTags(names []string, userID uint, workspaceID *uint) ([]models.Tag, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+
+	tags, err := s.tagRepo.FindByNames(unique, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		found[strings.ToLower(tag.Name)] = true
+	}
+	for _, name := range unique {
+		if found[strings.ToLower(name)] {
+			continue
+		}
+		tag := models.Tag{Name: name, UserID: userID, WorkspaceID: workspaceID}
+		if err := s.tagRepo.Create(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// diffTags returns the names of the tags added to and removed from a task
+func diffTags(before, after []models.Tag) (added, removed []string) {
+	had := make(map[uint]bool, len(before))
+	for _, tag := range before {
+		had[tag.ID] = true
+	}
+	has := make(map[uint]bool, len(after))
+	for _, tag := range after {
+		has[tag.ID] = true
+		if !had[tag.ID] {
+			added = append(added, tag.Name)
+		}
+	}
+	for _, tag := range before {
+		if !has[tag.ID] {
+			removed = append(removed, tag.Name)
+		}
+	}
+	return added, removed
+}
+
+// GetSeries retrieves a recurring task series
+func (s *TaskService) GetSeries(id uint, userID uint) (*models.TaskSeries, error) {
+	series, err := s.taskRepo.FindSeriesByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+// UpdateSeries changes the rule of a series and applies the given fields to its occurrences
+// that are not completed yet. Occurrences created later copy the latest occurrence.
+func (s *TaskService) UpdateSeries(id uint, userID uint, req models.UpdateSeriesRequest, requestID string) (*models.TaskSeries, error) {
+	series, err := s.taskRepo.FindSeriesByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrSeriesNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, series.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.RecurrenceRule != "" {
+		rule, err := utils.ParseRRule(req.RecurrenceRule)
+		if err != nil {
+			return nil, err
+		}
+		series.Rule = rule.String()
+	}
+	if req.CategoryID != nil && *req.CategoryID > 0 {
+		if err := s.validateCategory(*req.CategoryID, userID, series.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.taskRepo.UpdateSeries(series); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.FindOpenBySeries(series.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		before := tasks[i]
+		task := &tasks[i]
+		if req.Title != "" {
+			task.Title = req.Title
+		}
+		if req.Description != "" {
+			task.Description = req.Description
+		}
+		if req.Priority != "" {
+			task.Priority = req.Priority
+		}
+		if req.CategoryID != nil {
+			task.CategoryID = nil
+			if *req.CategoryID > 0 {
+				task.CategoryID = req.CategoryID
+			}
+		}
+
+		if err := s.taskRepo.Update(task); err != nil {
+			return nil, err
+		}
+		s.activityService.RecordTask(models.ActivityActionUpdated, &before, task, userID, requestID)
+	}
+
+	return series, nil
+}
+
+// StopSeries stops a series: completing its occurrences no longer creates new ones
+func (s *TaskService) StopSeries(id uint, userID uint) (*models.TaskSeries, error) {
+	series, err := s.taskRepo.FindSeriesByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrSeriesNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, series.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if series.StoppedAt == nil {
+		now := time.Now()
+		series.StoppedAt = &now
+		if err := s.taskRepo.UpdateSeries(series); err != nil {
+			return nil, err
+		}
+	}
+	return series, nil
+}
+
+// createNextOccurrences creates the next occurrence of each recurring series of the completed
+// tasks. Nothing is created when the series is stopped or has ended, or when a later occurrence
+// already exists, e.g. because an occurrence was completed, reopened and completed again.
+func (s *TaskService) createNextOccurrences(completed []models.Task, userID uint, requestID string) error {
+	workflows := newWorkflowCache(s.workflowRepo)
+	for i := range completed {
+		if completed[i].SeriesID == nil || completed[i].DueDate == nil {
+			continue
+		}
+
+		// Reload with the series and the assignees, which the next occurrence keeps
+		task, err := s.taskRepo.FindByID(completed[i].ID, userID)
+		if err != nil {
+			return err
+		}
+		series := task.Series
+		if series == nil || series.StoppedAt != nil || task.DueDate == nil {
+			continue
+		}
+		rule, err := utils.ParseRRule(series.Rule)
+		if err != nil {
+			log.Printf("❌ Invalid rule of task series %d: %v", series.ID, err)
+			continue
+		}
+		dueDate, ok := rule.Next(series.StartAt, *task.DueDate)
+		if !ok {
+			continue
+		}
+
+		exists, err := s.taskRepo.HasLaterOccurrence(series.ID, *task.DueDate)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		// The next occurrence starts in the first todo status of the workflow
+		workflow, err := workflows.forTask(task)
+		if err != nil {
+			return err
+		}
+
+		var assignees []models.TaskAssignee
+		for _, assignee := range task.Assignees {
+			assignees = append(assignees, models.TaskAssignee{UserID: assignee.UserID, AssignedByID: assignee.AssignedByID})
+		}
+		next := &models.Task{
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      models.TaskStatusPending,
+			Priority:    task.Priority,
+			DueDate:     &dueDate,
+			UserID:      task.UserID,
+			WorkspaceID: task.WorkspaceID,
+			ParentID:    task.ParentID,
+			SeriesID:    task.SeriesID,
+			CategoryID:  task.CategoryID,
+			Assignees:   assignees,
+			Tags:        task.Tags,
+
+			WorkflowStatus:  workflow.FirstStatus(models.StatusCategoryTodo).Key,
+			EstimateMinutes: task.EstimateMinutes,
+			StoryPoints:     task.StoryPoints,
+		}
+		if err := s.taskRepo.Create(next); err != nil {
+			return err
+		}
+		s.activityService.RecordTask(models.ActivityActionCreated, nil, next, userID, requestID)
+	}
+	return nil
+}
+
+// AddDependency marks a task as blocked by another task of the same workspace
+func (s *TaskService) AddDependency(id uint, userID uint, req models.AddDependencyRequest, requestID string) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	blocker, err := s.taskRepo.FindByID(req.BlockerID, userID)
+	if err != nil {
+		return nil, utils.ErrBlockerNotFound
+	}
+	if !sameWorkspace(blocker.WorkspaceID, task.WorkspaceID) {
+		return nil, utils.ErrDependencyWorkspaceMismatch
+	}
+
+	// The blocker must not depend on the task, directly or through other tasks
+	if blocker.ID == task.ID {
+		return nil, utils.ErrDependencyCycle
+	}
+	cyclic, err := s.isBlockedBy(blocker.ID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cyclic {
+		return nil, utils.ErrDependencyCycle
+	}
+
+	added, err := s.taskRepo.AddDependency(task.ID, blocker.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		s.activityService.RecordTaskBlockers(task, []uint{blocker.ID}, nil, userID, requestID)
+	}
+
+	return s.GetTaskByID(task.ID, userID, models.TaskQuery{})
+}
+
+// RemoveDependency removes a blocker from a task
+func (s *TaskService) RemoveDependency(id uint, userID uint, blockerID uint, requestID string) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrTaskNotFound
+	}
+	if err := requireWorkspaceEditor(s.workspaceRepo, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.RemoveDependency(task.ID, blockerID); err != nil {
+		if err.Error() == "dependency not found" {
+			return nil, utils.ErrDependencyNotFound
+		}
+		return nil, err
+	}
+
+	s.activityService.RecordTaskBlockers(task, nil, []uint{blockerID}, userID, requestID)
+	return s.GetTaskByID(task.ID, userID, models.TaskQuery{})
+}
+
+// isBlockedBy reports whether a task depends on another task, directly or through other tasks
+func (s *TaskService) isBlockedBy(id uint, blockerID uint) (bool, error) {
+	seen := map[uint]bool{id: true}
+	level := []uint{id}
+	for len(level) > 0 {
+		blockerIDs, err := s.taskRepo.FindBlockerIDs(level)
+		if err != nil {
+			return false, err
+		}
+
+		level = nil
+		for _, candidate := range blockerIDs {
+			if candidate == blockerID {
+				return true, nil
+			}
+			if !seen[candidate] {
+				seen[candidate] = true
+				level = append(level, candidate)
+			}
+		}
+	}
+	return false, nil
+}
+
+// blockable reports whether open blockers prevent moving a task to a status
+func (s *TaskService) blockable(status models.TaskStatus) bool {
+	return status == models.TaskStatusCompleted || (status == models.TaskStatusInProgress && s.opts.BlockersPreventStart)
+}
+
+// checkNotBlocked refuses to move a task with open blockers to a status they prevent
+func (s *TaskService) checkNotBlocked(id uint, status models.TaskStatus) error {
+	if !s.blockable(status) {
+		return nil
+	}
+
+	dependencies, err := s.taskRepo.FindOpenBlockers([]uint{id})
+	if err != nil {
+		return err
+	}
+	if len(dependencies) > 0 {
+		return utils.ErrTaskBlocked
+	}
+	return nil
+}
+
+// withoutOpenBlockers removes the tasks that open blockers prevent from moving to a status.
+// Blockers completed by the same request do not count.
+func (s *TaskService) withoutOpenBlockers(tasks []models.Task, status models.TaskStatus) ([]models.Task, error) {
+	if !s.blockable(status) || len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	dependencies, err := s.taskRepo.FindOpenBlockers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		blockers[dependency.TaskID] = append(blockers[dependency.TaskID], dependency.BlockerID)
+	}
+
+	allowed := make(map[uint]bool, len(tasks))
+	changes := make(map[uint]bool, len(tasks))
+	for _, task := range tasks {
+		allowed[task.ID] = true
+		changes[task.ID] = task.Status != status
+	}
+	for changed := true; changed; {
+		changed = false
+		for id := range allowed {
+			if !changes[id] {
+				continue // already in the status, nothing changes
+			}
+			for _, blockerID := range blockers[id] {
+				if status != models.TaskStatusCompleted || !allowed[blockerID] {
+					delete(allowed, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	remaining := make([]models.Task, 0, len(allowed))
+	for _, task := range tasks {
+		if allowed[task.ID] {
+			remaining = append(remaining, task)
+		}
+	}
+	return remaining, nil
+}
+
+// validateMove checks that a task can be moved below a new parent: the parent must be visible,
+// in the same workspace, not the task itself or one of its subtasks, and the moved subtree must
+// stay within the depth limit
+func (s *TaskService) validateMove(task *models.Task, parentID uint, userID uint) error {
+	parent, err := s.taskRepo.FindByID(parentID, userID)
+	if err != nil {
+		return utils.ErrParentTaskNotFound
+	}
+	if !sameWorkspace(parent.WorkspaceID, task.WorkspaceID) {
+		return utils.ErrParentWorkspaceMismatch
+	}
+
+	ancestors, err := s.ancestorsOf(parent.ID)
+	if err != nil {
+		return err
+	}
+	for _, ancestorID := range ancestors {
+		if ancestorID == task.ID {
+			return utils.ErrTaskHierarchyCycle
+		}
+	}
+
+	// Height of the moved subtree: descendants come after their parents
+	descendants, err := s.taskRepo.FindDescendants([]uint{task.ID})
+	if err != nil {
+		return err
+	}
+	levels := map[uint]int{task.ID: 1}
+	height := 1
+	for _, descendant := range descendants {
+		level := levels[*descendant.ParentID] + 1
+		levels[descendant.ID] = level
+		if level > height {
+			height = level
+		}
+	}
+
+	if len(ancestors)+height > s.opts.MaxDepth {
+		return utils.ErrTaskDepthExceeded
+	}
+	return nil
+}
+
+// validateDepth checks that a subtree of the given height fits below a parent
+func (s *TaskService) validateDepth(parentID uint, height int) error {
+	ancestors, err := s.ancestorsOf(parentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+height > s.opts.MaxDepth {
+		return utils.ErrTaskDepthExceeded
+	}
+	return nil
+}
+
+// ancestorsOf returns a task followed by its parents up to the top-level task. The walk stops
+// once it is longer than the depth limit, which is enough to reject a move or a new subtask.
+func (s *TaskService) ancestorsOf(id uint) ([]uint, error) {
+	ancestors := []uint{id}
+	for len(ancestors) <= s.opts.MaxDepth {
+		parentID, err := s.taskRepo.FindParentID(ancestors[len(ancestors)-1])
+		if err != nil {
+			return nil, err
+		}
+		if parentID == nil {
+			break
+		}
+		ancestors = append(ancestors, *parentID)
+	}
+	return ancestors, nil
+}
+
+// checkCanComplete refuses to complete a task with open subtasks when completing parents is blocked
+func (s *TaskService) checkCanComplete(id uint) error {
+	if s.opts.CompleteParent != models.SubtaskBehaviorBlock {
+		return nil
+	}
+
+	descendants, err := s.taskRepo.FindDescendants([]uint{id})
+	if err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant.Status != models.TaskStatusCompleted {
+			return utils.ErrOpenSubtasks
+		}
+	}
+	return nil
+}
+
+// withoutOpenSubtasks removes the tasks that have open subtasks outside of the given tasks.
+// Removing a task can block its parent in turn, so this repeats until nothing changes.
+func (s *TaskService) withoutOpenSubtasks(tasks []models.Task) ([]models.Task, error) {
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	descendants, err := s.taskRepo.FindDescendants(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.Task)
+	for _, task := range append(descendants, tasks...) {
+		if task.ParentID != nil {
+			children[*task.ParentID] = append(children[*task.ParentID], task)
+		}
+	}
+
+	allowed := make(map[uint]bool, len(tasks))
+	open := make(map[uint]bool, len(tasks))
+	for _, task := range tasks {
+		allowed[task.ID] = true
+		open[task.ID] = task.Status != models.TaskStatusCompleted
+	}
+	for changed := true; changed; {
+		changed = false
+		for id := range allowed {
+			if !open[id] {
+				continue // already completed, nothing changes
+			}
+			for _, child := range children[id] {
+				if child.Status != models.TaskStatusCompleted && !allowed[child.ID] {
+					delete(allowed, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	remaining := make([]models.Task, 0, len(allowed))
+	for _, task := range tasks {
+		if allowed[task.ID] {
+			remaining = append(remaining, task)
+		}
+	}
+	return remaining, nil
+}
+
+// completeSubtasks completes or detaches the open subtasks of tasks that were just completed
+func (s *TaskService) completeSubtasks(ids []uint, userID uint, requestID string) error {
+	var subtasks []models.Task
+	var err error
+	switch s.opts.CompleteParent {
+	case models.SubtaskBehaviorCascade:
+		subtasks, err = s.taskRepo.FindDescendants(ids)
+	case models.SubtaskBehaviorDetach:
+		subtasks, err = s.taskRepo.FindChildren(ids)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Cascaded subtasks move to the first done status of their workflow, bypassing its transitions
+	var openIDs []uint
+	doneIDs := make(map[string][]uint)
+	workflows := newWorkflowCache(s.workflowRepo)
+	var activities []models.Activity
+	for i := range subtasks {
+		if subtasks[i].Status == models.TaskStatusCompleted {
+			continue
+		}
+		openIDs = append(openIDs, subtasks[i].ID)
+
+		after := subtasks[i]
+		action := models.ActivityActionUpdated
+		if s.opts.CompleteParent == models.SubtaskBehaviorCascade {
+			workflow, err := workflows.forTask(&subtasks[i])
+			if err != nil {
+				return err
+			}
+			after.Status = models.TaskStatusCompleted
+			after.WorkflowStatus = workflow.FirstStatus(models.StatusCategoryDone).Key
+			doneIDs[after.WorkflowStatus] = append(doneIDs[after.WorkflowStatus], after.ID)
+			action = models.ActivityActionStatusChanged
+		} else {
+			after.ParentID = nil
+		}
+		activities = append(activities, taskActivity(action, &subtasks[i], &after, userID, requestID))
+	}
+	if len(openIDs) == 0 {
+		return nil
+	}
+
+	if s.opts.CompleteParent == models.SubtaskBehaviorCascade {
+		for key, ids := range doneIDs {
+			if _, err = s.taskRepo.BulkUpdateStatus(ids, userID, models.TaskStatusCompleted, key); err != nil {
+				break
+			}
+		}
+	} else {
+		err = s.taskRepo.SetParent(openIDs, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.activityService.Record(activities...)
+	return nil
+}
+
+// releaseSubtasks blocks, deletes or detaches the subtasks of a task that is about to be deleted
+func (s *TaskService) releaseSubtasks(id uint, userID uint, requestID string) error {
+	children, err := s.taskRepo.FindChildren([]uint{id})
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	switch s.opts.DeleteParent {
+	case models.SubtaskBehaviorCascade:
+		descendants, err := s.taskRepo.FindDescendants([]uint{id})
+		if err != nil {
+			return err
+		}
+		descendantIDs := make([]uint, len(descendants))
+		activities := make([]models.Activity, len(descendants))
+		for i := range descendants {
+			descendantIDs[i] = descendants[i].ID
+			activities[i] = taskActivity(models.ActivityActionDeleted, &descendants[i], nil, userID, requestID)
+		}
+		if err := s.taskRepo.DeleteByIDs(descendantIDs); err != nil {
+			return err
+		}
+		s.activityService.Record(activities...)
+
+	case models.SubtaskBehaviorDetach:
+		childIDs := make([]uint, len(children))
+		activities := make([]models.Activity, len(children))
+		for i := range children {
+			childIDs[i] = children[i].ID
+			after := children[i]
+			after.ParentID = nil
+			activities[i] = taskActivity(models.ActivityActionUpdated, &children[i], &after, userID, requestID)
+		}
+		if err := s.taskRepo.SetParent(childIDs, nil); err != nil {
+			return err
+		}
+		s.activityService.Record(activities...)
+
+	default:
+		return utils.ErrTaskHasSubtasks
+	}
+	return nil
+}
+
+// uniqueIDs removes duplicate IDs while keeping their order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// positiveOrNil treats zero as no value for optional numbers such as estimates
+func positiveOrNil(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+type TimeEntryService struct {
+	timeEntryRepo *repository.TimeEntryRepository
+	taskRepo      *repository.TaskRepository
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewTimeEntryService creates a new time entry service
+func NewTimeEntryService(timeEntryRepo *repository.TimeEntryRepository, taskRepo *repository.TaskRepository, workspaceRepo *repository.WorkspaceRepository) *TimeEntryService {
+	return &TimeEntryService{
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// GetRunning retrieves the running timer of the user, nil when none is running
+func (s *TimeEntryService) GetRunning(userID uint) (*models.TimeEntry, error) {
+	return s.timeEntryRepo.FindRunningByUser(userID)
+}
+
+// StartTimer starts tracking time on a task the user can edit. A user has at most one running timer.
+func (s *TimeEntryService) StartTimer(taskID uint, userID uint, req models.StartTimerRequest) (*models.TimeEntry, error) {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	running, err := s.timeEntryRepo.FindRunningByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		// A timer on a task the user can no longer see (deleted, or the user left the
+		// workspace) would block the user forever, so it is stopped instead
+		visible, err := s.taskRepo.ExistsByID(running.TaskID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			return nil, utils.ErrTimerAlreadyRunning
+		}
+		if err := s.stop(running); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		// The unique index on running timers rejects a timer started concurrently
+		if running, _ := s.timeEntryRepo.FindRunningByUser(userID); running != nil {
+			return nil, utils.ErrTimerAlreadyRunning
+		}
+		return nil, err
+	}
+
+	// Reload with the user
+	return s.timeEntryRepo.FindByID(entry.ID, taskID)
+}
+
+// StopTimer stops the user's running timer on a task
+func (s *TimeEntryService) StopTimer(taskID uint, userID uint) (*models.TimeEntry, error) {
+	running, err := s.timeEntryRepo.FindRunningByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil || running.TaskID != taskID {
+		return nil, utils.ErrTimerNotRunning
+	}
+
+	if err := s.stop(running); err != nil {
+		return nil, err
+	}
+	return running, nil
+}
+
+// GetAllByTask retrieves the time entries of a task visible to the user, newest first
+func (s *TimeEntryService) GetAllByTask(taskID uint, userID uint) ([]models.TimeEntry, error) {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrTaskNotFound
+	}
+	return s.timeEntryRepo.FindAllByTask(taskID)
+}
+
+// Create records time spent on a task the user can edit without a timer
+func (s *TimeEntryService) Create(taskID uint, userID uint, req models.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	if err := requireEditableTask(s.taskRepo, s.workspaceRepo, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.validatePeriod(req.StartedAt, req.EndedAt, userID, 0); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		TaskID:          taskID,
+		UserID:          userID,
+		StartedAt:       req.StartedAt,
+		EndedAt:         &req.EndedAt,
+		DurationSeconds: durationSeconds(req.StartedAt, req.EndedAt),
+		Note:            strings.TrimSpace(req.Note),
+		Manual:          true,
+	}
+	if err := s.timeEntryRepo.Create(entry); err != nil {
+		return nil, err
+	}
+
+	// Reload with the user
+	return s.timeEntryRepo.FindByID(entry.ID, taskID)
+}
+
+// Update changes the period or note of the user's own time entry. The period of a running
+// timer cannot be changed.
+func (s *TimeEntryService) Update(id uint, taskID uint, userID uint, req models.UpdateTimeEntryRequest) (*models.TimeEntry, error) {
+	entry, err := s.ownEntry(id, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.StartedAt != nil || req.EndedAt != nil {
+		if entry.Running() {
+			return nil, utils.ErrTimeEntryRunning
+		}
+
+		startedAt, endedAt := entry.StartedAt, *entry.EndedAt
+		if req.StartedAt != nil {
+			startedAt = *req.StartedAt
+		}
+		if req.EndedAt != nil {
+			endedAt = *req.EndedAt
+		}
+		if err := s.validatePeriod(startedAt, endedAt, userID, entry.ID); err != nil {
+			return nil, err
+		}
+
+		entry.StartedAt = startedAt
+		entry.EndedAt = &endedAt
+		entry.DurationSeconds = durationSeconds(startedAt, endedAt)
+	}
+	if req.Note != nil {
+		entry.Note = strings.TrimSpace(*req.Note)
+	}
+
+	if err := s.timeEntryRepo.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Delete removes the user's own time entry; deleting a running timer discards it
+func (s *TimeEntryService) Delete(id uint, taskID uint, userID uint) error {
+	entry, err := s.ownEntry(id, taskID, userID)
+	if err != nil {
+		return err
+	}
+	return s.timeEntryRepo.Delete(entry.ID)
+}
+
+// stop ends a running timer now
+func (s *TimeEntryService) stop(entry *models.TimeEntry) error {
+	now := time.Now()
+	entry.EndedAt = &now
+	entry.DurationSeconds = durationSeconds(entry.StartedAt, now)
+	return s.timeEntryRepo.Update(entry)
+}
+
+// validatePeriod checks that a period ends after it starts, lies in the past and does not
+// overlap the user's other entries
+func (s *TimeEntryService) validatePeriod(startedAt, endedAt time.Time, userID uint, excludeID uint) error {
+	if !endedAt.After(startedAt) {
+		return utils.ErrInvalidTimeEntry
+	}
+	if endedAt.After(time.Now()) {
+		return utils.ErrTimeEntryInFuture
+	}
+
+	overlaps, err := s.timeEntryRepo.HasOverlap(userID, startedAt, &endedAt, excludeID)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return utils.ErrTimeEntryOverlap
+	}
+	return nil
+}
+
+// ownEntry finds an entry of a task visible to the user and checks that the user owns it
+func (s *TimeEntryService) ownEntry(id uint, taskID uint, userID uint) (*models.TimeEntry, error) {
+	exists, err := s.taskRepo.ExistsByID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrTaskNotFound
+	}
+
+	entry, err := s.timeEntryRepo.FindByID(id, taskID)
+	if err != nil {
+		return nil, utils.ErrTimeEntryNotFound
+	}
+	if entry.UserID != userID {
+		return nil, utils.ErrNotTimeEntryOwner
+	}
+	return entry, nil
+}
+
+// durationSeconds returns the whole seconds between two times
+func durationSeconds(startedAt, endedAt time.Time) int64 {
+	return int64(endedAt.Sub(startedAt) / time.Second)
+}
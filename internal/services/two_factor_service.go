@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// recoveryCodeCount is the number of recovery codes generated per user
+const recoveryCodeCount = 10
+
+type TwoFactorService struct {
+	userRepo         *repository.UserRepository
+	recoveryCodeRepo *repository.RecoveryCodeRepository
+	issuer           string
+}
+
+// NewTwoFactorService creates a new two-factor service
+func NewTwoFactorService(userRepo *repository.UserRepository, recoveryCodeRepo *repository.RecoveryCodeRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		issuer:           issuer,
+	}
+}
+
+// Setup generates a new pending TOTP secret. It becomes active once confirmed with a code.
+func (s *TwoFactorService) Setup(userID uint) (*models.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, utils.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+
+	if err := s.userRepo.UpdateTwoFactor(user.ID, secret, 0, nil); err != nil {
+		return nil, errors.New("failed to save secret")
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm activates two-factor authentication with a first valid code and returns recovery codes
+func (s *TwoFactorService) Confirm(userID uint, code string) (*models.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, utils.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, utils.ErrTwoFactorNotSetUp
+	}
+
+	counter, ok := utils.ValidateTOTPCode(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateTwoFactor(user.ID, user.TOTPSecret, counter, &now); err != nil {
+		return nil, errors.New("failed to enable two-factor authentication")
+	}
+
+	return s.generateRecoveryCodes(user.ID)
+}
+
+// Disable turns off two-factor authentication after checking the password and a second factor
+func (s *TwoFactorService) Disable(userID uint, req *models.DisableTwoFactorRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return utils.ErrUserNotFound
+	}
+	if !user.IsTwoFactorEnabled() {
+		return utils.ErrTwoFactorNotEnabled
+	}
+	if !user.CheckPassword(req.Password) {
+		return utils.ErrIncorrectPassword
+	}
+
+	ok, err := s.VerifySecondFactor(user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return utils.ErrInvalidTwoFactorCode
+	}
+
+	if err := s.userRepo.UpdateTwoFactor(user.ID, "", 0, nil); err != nil {
+		return errors.New("failed to disable two-factor authentication")
+	}
+
+	return s.recoveryCodeRepo.DeleteAllByUser(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a TOTP code
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) (*models.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, utils.ErrTwoFactorNotEnabled
+	}
+
+	ok, err := s.verifyTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	return s.generateRecoveryCodes(user.ID)
+}
+
+// VerifySecondFactor checks a TOTP code or, failing that, consumes a recovery code
+func (s *TwoFactorService) VerifySecondFactor(user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return s.verifyTOTP(user, code)
+	}
+
+	return s.recoveryCodeRepo.Consume(user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// verifyTOTP validates a TOTP code and rejects codes that were already used. The used time
+// step is recorded with a conditional update, so concurrent requests cannot both accept a code.
+func (s *TwoFactorService) verifyTOTP(user *models.User, code string) (bool, error) {
+	counter, ok := utils.ValidateTOTPCode(user.TOTPSecret, code, time.Now())
+	if !ok || counter <= user.TOTPLastCounter {
+		return false, nil
+	}
+
+	consumed, err := s.userRepo.ConsumeTOTPCounter(user.ID, counter)
+	if err != nil || !consumed {
+		return false, err
+	}
+	user.TOTPLastCounter = counter
+	return true, nil
+}
+
+// generateRecoveryCodes creates and stores a fresh set of recovery codes
+func (s *TwoFactorService) generateRecoveryCodes(userID uint) (*models.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.GenerateRandomToken(5)
+		if err != nil {
+			return nil, errors.New("failed to generate recovery codes")
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(raw)
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceAll(userID, hashes); err != nil {
+		return nil, errors.New("failed to save recovery codes")
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// normalizeRecoveryCode strips separators and case so codes can be typed loosely
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// isTOTPCode reports whether s looks like a six digit TOTP code
+func isTOTPCode(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
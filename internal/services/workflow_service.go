@@ -0,0 +1,335 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// statusKeyPattern restricts status keys to values that are safe in URLs and filters
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type WorkflowService struct {
+	workflowRepo  *repository.WorkflowRepository
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewWorkflowService creates a new workflow service
+func NewWorkflowService(workflowRepo *repository.WorkflowRepository, workspaceRepo *repository.WorkspaceRepository) *WorkflowService {
+	return &WorkflowService{
+		workflowRepo:  workflowRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// Create defines the personal workflow of the user, or the workflow of a workspace the user
+// manages. Existing tasks move to the first status of their category unless a status with
+// the same key exists, which must then have the same category as the task.
+func (s *WorkflowService) Create(req *models.CreateWorkflowRequest, userID uint) (*models.Workflow, error) {
+	workspaceID := req.WorkspaceID
+	if workspaceID != nil && *workspaceID == 0 {
+		workspaceID = nil
+	}
+	if err := requireWorkspaceManager(s.workspaceRepo, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.workflowRepo.FindByScope(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, utils.ErrWorkflowExists
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", utils.ErrInvalidWorkflow)
+	}
+	statuses, transitions, err := buildWorkflow(req.Statuses, req.Transitions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCategoryChanges(workspaceID, userID, models.DefaultWorkflow().Statuses, statuses); err != nil {
+		return nil, err
+	}
+
+	workflow := &models.Workflow{
+		Name:        name,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Statuses:    statuses,
+		Transitions: transitions,
+	}
+	if err := s.workflowRepo.Create(workflow); err != nil {
+		// The unique indexes reject a workflow created concurrently for the same scope
+		if existing, _ := s.workflowRepo.FindByScope(workspaceID, userID); existing != nil {
+			return nil, utils.ErrWorkflowExists
+		}
+		return nil, statusInUseError(err)
+	}
+	return s.workflowRepo.FindByID(workflow.ID, userID)
+}
+
+// GetByID retrieves a workflow by ID for a specific user
+func (s *WorkflowService) GetByID(id uint, userID uint) (*models.Workflow, error) {
+	workflow, err := s.workflowRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrWorkflowNotFound
+	}
+	return workflow, nil
+}
+
+// GetAllByUser retrieves the workflows visible to a user.
+// A non-zero workspaceID limits the result to that workspace.
+func (s *WorkflowService) GetAllByUser(userID uint, workspaceID uint) ([]models.Workflow, error) {
+	if workspaceID > 0 {
+		if err := s.requireMember(workspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.workflowRepo.FindAllByUser(userID, workspaceID)
+}
+
+// GetEffective retrieves the workflow that applies to the user's personal tasks, or to the
+// tasks of a workspace when workspaceID is non-zero, which is the default workflow unless
+// one was defined
+func (s *WorkflowService) GetEffective(userID uint, workspaceID uint) (*models.Workflow, error) {
+	if workspaceID == 0 {
+		return effectiveWorkflow(s.workflowRepo, nil, userID)
+	}
+	if err := s.requireMember(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return effectiveWorkflow(s.workflowRepo, &workspaceID, userID)
+}
+
+// Update renames a workflow or replaces its statuses or transitions. Tasks in a status that
+// was removed move to the first status of their category. The category of a status can only
+// change while no tasks are in it.
+func (s *WorkflowService) Update(id uint, req *models.UpdateWorkflowRequest, userID uint) (*models.Workflow, error) {
+	workflow, err := s.workflowRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrWorkflowNotFound
+	}
+	if err := requireWorkspaceManager(s.workspaceRepo, workflow.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		workflow.Name = name
+	}
+
+	// Omitted lists keep their current entries, which are checked against the new statuses
+	statuses := req.Statuses
+	if statuses == nil {
+		for _, status := range workflow.Statuses {
+			statuses = append(statuses, models.WorkflowStatusInput{Key: status.Key, Name: status.Name, Category: status.Category})
+		}
+	}
+	transitions := req.Transitions
+	if transitions == nil {
+		for _, transition := range workflow.Transitions {
+			transitions = append(transitions, models.WorkflowTransitionInput{From: transition.From, To: transition.To})
+		}
+	}
+	current := workflow.Statuses
+	if workflow.Statuses, workflow.Transitions, err = buildWorkflow(statuses, transitions); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategoryChanges(workflow.WorkspaceID, workflow.UserID, current, workflow.Statuses); err != nil {
+		return nil, err
+	}
+
+	if err := s.workflowRepo.Update(workflow); err != nil {
+		return nil, statusInUseError(err)
+	}
+	return s.workflowRepo.FindByID(workflow.ID, userID)
+}
+
+// Delete removes a workflow; its tasks go back to the default workflow
+func (s *WorkflowService) Delete(id uint, userID uint) error {
+	workflow, err := s.workflowRepo.FindByID(id, userID)
+	if err != nil {
+		return utils.ErrWorkflowNotFound
+	}
+	if err := requireWorkspaceManager(s.workspaceRepo, workflow.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	return s.workflowRepo.Delete(workflow)
+}
+
+// requireMember checks that the user is a member of a workspace
+func (s *WorkflowService) requireMember(workspaceID uint, userID uint) error {
+	isMember, err := s.workspaceRepo.IsMember(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return utils.ErrWorkspaceNotFound
+	}
+	return nil
+}
+
+// checkCategoryChanges rejects moving a status that tasks are in to another category. Their
+// category would change without the checks of a status change (blockers, subtasks, the next
+// occurrence of recurring tasks), so the tasks have to be moved to another status first.
+func (s *WorkflowService) checkCategoryChanges(workspaceID *uint, userID uint, current, next []models.WorkflowStatus) error {
+	inUse, err := s.workflowRepo.FindStatusesInUse(workspaceID, userID, recategorizedStatuses(current, next))
+	if err != nil {
+		return err
+	}
+	if len(inUse) > 0 {
+		return fmt.Errorf("%w: %s", utils.ErrStatusInUse, strings.Join(inUse, ", "))
+	}
+	return nil
+}
+
+// statusInUseError maps the repository error for tasks whose category would change, which
+// happens when tasks moved into such a status after checkCategoryChanges
+func statusInUseError(err error) error {
+	if err.Error() == "status in use" {
+		return utils.ErrStatusInUse
+	}
+	return err
+}
+
+// recategorizedStatuses returns the keys of statuses that exist in both lists with
+// different categories
+func recategorizedStatuses(current, next []models.WorkflowStatus) []string {
+	categories := make(map[string]models.StatusCategory, len(current))
+	for _, status := range current {
+		categories[status.Key] = status.Category
+	}
+
+	var keys []string
+	for _, status := range next {
+		if category, ok := categories[status.Key]; ok && category != status.Category {
+			keys = append(keys, status.Key)
+		}
+	}
+	return keys
+}
+
+// buildWorkflow validates the statuses and transitions of a workflow. Every category needs
+// at least one status, and transitions must connect two different statuses of the workflow.
+func buildWorkflow(statusInputs []models.WorkflowStatusInput, transitionInputs []models.WorkflowTransitionInput) ([]models.WorkflowStatus, []models.WorkflowTransition, error) {
+	statuses := make([]models.WorkflowStatus, 0, len(statusInputs))
+	keys := make(map[string]bool, len(statusInputs))
+	categories := make(map[models.StatusCategory]bool)
+	for i, input := range statusInputs {
+		key := strings.TrimSpace(input.Key)
+		if !statusKeyPattern.MatchString(key) {
+			return nil, nil, fmt.Errorf("%w: status key %q must start with a lowercase letter and contain only lowercase letters, digits and underscores", utils.ErrInvalidWorkflow, key)
+		}
+		if keys[key] {
+			return nil, nil, fmt.Errorf("%w: duplicate status key %q", utils.ErrInvalidWorkflow, key)
+		}
+		keys[key] = true
+		categories[input.Category] = true
+
+		name := strings.TrimSpace(input.Name)
+		if name == "" {
+			name = key
+		}
+		statuses = append(statuses, models.WorkflowStatus{Key: key, Name: name, Category: input.Category, Position: i})
+	}
+	for _, category := range models.StatusCategories {
+		if !categories[category] {
+			return nil, nil, fmt.Errorf("%w: at least one %s status is required", utils.ErrInvalidWorkflow, category)
+		}
+	}
+
+	transitions := make([]models.WorkflowTransition, 0, len(transitionInputs))
+	seen := make(map[models.WorkflowTransitionInput]bool, len(transitionInputs))
+	for _, input := range transitionInputs {
+		input.From, input.To = strings.TrimSpace(input.From), strings.TrimSpace(input.To)
+		for _, key := range []string{input.From, input.To} {
+			if !keys[key] {
+				return nil, nil, fmt.Errorf("%w: transition refers to unknown status %q", utils.ErrInvalidWorkflow, key)
+			}
+		}
+		if input.From == input.To {
+			return nil, nil, fmt.Errorf("%w: transition from %q to itself", utils.ErrInvalidWorkflow, input.From)
+		}
+		if seen[input] {
+			continue
+		}
+		seen[input] = true
+		transitions = append(transitions, models.WorkflowTransition{From: input.From, To: input.To})
+	}
+	return statuses, transitions, nil
+}
+
+// effectiveWorkflow returns the workflow of a workspace, or of the user's personal tasks when
+// workspaceID is nil, falling back to the default workflow
+func effectiveWorkflow(workflowRepo *repository.WorkflowRepository, workspaceID *uint, userID uint) (*models.Workflow, error) {
+	workflow, err := workflowRepo.FindByScope(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workflow == nil {
+		workflow = models.DefaultWorkflow()
+		workflow.WorkspaceID = workspaceID
+		if workspaceID == nil {
+			workflow.UserID = userID
+		}
+	}
+	return workflow, nil
+}
+
+// workflowScope identifies the workflow of a task: its workspace, or its owner for personal tasks
+type workflowScope struct {
+	workspaceID uint
+	userID      uint
+}
+
+// workflowCache looks up the workflow of each scope once while handling many tasks
+type workflowCache struct {
+	workflowRepo *repository.WorkflowRepository
+	workflows    map[workflowScope]*models.Workflow
+}
+
+// newWorkflowCache creates an empty workflow cache
+func newWorkflowCache(workflowRepo *repository.WorkflowRepository) *workflowCache {
+	return &workflowCache{
+		workflowRepo: workflowRepo,
+		workflows:    make(map[workflowScope]*models.Workflow),
+	}
+}
+
+// forTask returns the workflow that applies to a task
+func (c *workflowCache) forTask(task *models.Task) (*models.Workflow, error) {
+	scope := workflowScope{userID: task.UserID}
+	if task.WorkspaceID != nil {
+		scope = workflowScope{workspaceID: *task.WorkspaceID}
+	}
+	if workflow, ok := c.workflows[scope]; ok {
+		return workflow, nil
+	}
+
+	workflow, err := effectiveWorkflow(c.workflowRepo, task.WorkspaceID, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	c.workflows[scope] = workflow
+	return workflow, nil
+}
+
+// transitionTo finds the status of a task's workflow with the key and checks that the
+// workflow allows the task to move there
+func transitionTo(workflow *models.Workflow, task *models.Task, key string) (*models.WorkflowStatus, error) {
+	status := workflow.Status(key)
+	if status == nil {
+		return nil, fmt.Errorf("%w: task %d has no status %q", utils.ErrUnknownStatus, task.ID, key)
+	}
+	if !workflow.Allows(task.WorkflowStatus, key) {
+		return nil, fmt.Errorf("%w: task %d cannot move from %q to %q", utils.ErrIllegalTransition, task.ID, task.WorkflowStatus, key)
+	}
+	return status, nil
+}
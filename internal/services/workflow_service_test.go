@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/database"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRecategorizedStatuses(t *testing.T) {
+	current := []models.WorkflowStatus{
+		{Key: "backlog", Category: models.StatusCategoryTodo},
+		{Key: "doing", Category: models.StatusCategoryDoing},
+		{Key: "review", Category: models.StatusCategoryDoing},
+		{Key: "done", Category: models.StatusCategoryDone},
+	}
+
+	tests := []struct {
+		name string
+		next []models.WorkflowStatus
+		want []string
+	}{
+		{
+			name: "same statuses",
+			next: current,
+		},
+		{
+			name: "removed and added statuses",
+			next: []models.WorkflowStatus{
+				{Key: "backlog", Category: models.StatusCategoryTodo},
+				{Key: "doing", Category: models.StatusCategoryDoing},
+				{Key: "shipped", Category: models.StatusCategoryDone},
+			},
+		},
+		{
+			name: "status moved to done",
+			next: []models.WorkflowStatus{
+				{Key: "backlog", Category: models.StatusCategoryTodo},
+				{Key: "doing", Category: models.StatusCategoryDoing},
+				{Key: "review", Category: models.StatusCategoryDone},
+				{Key: "done", Category: models.StatusCategoryDone},
+			},
+			want: []string{"review"},
+		},
+		{
+			name: "categories swapped",
+			next: []models.WorkflowStatus{
+				{Key: "backlog", Category: models.StatusCategoryDoing},
+				{Key: "doing", Category: models.StatusCategoryTodo},
+				{Key: "done", Category: models.StatusCategoryDone},
+			},
+			want: []string{"backlog", "doing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recategorizedStatuses(current, tt.next); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recategorizedStatuses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// openTestDB connects to the database in TEST_DATABASE_URL and migrates it, skipping the test
+// when no database is configured
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	database.DB = db
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatalf("migrating the test database: %v", err)
+	}
+	return db
+}
+
+// TestWorkflowUpdateRefusesRecategorizingBlockedTask moves a status that holds a blocked task
+// into the done category, which would complete the task without checking its blocker
+func TestWorkflowUpdateRefusesRecategorizingBlockedTask(t *testing.T) {
+	db := openTestDB(t)
+
+	suffix := time.Now().UnixNano()
+	user := &models.User{
+		Username: fmt.Sprintf("workflow_test_%d", suffix),
+		Email:    fmt.Sprintf("workflow_test_%d@example.com", suffix),
+		Password: "unused",
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM task_dependencies WHERE task_id IN (SELECT id FROM tasks WHERE user_id = ?)", user.ID)
+		db.Exec("DELETE FROM workflow_statuses WHERE workflow_id IN (SELECT id FROM workflows WHERE user_id = ?)", user.ID)
+		db.Exec("DELETE FROM workflow_transitions WHERE workflow_id IN (SELECT id FROM workflows WHERE user_id = ?)", user.ID)
+		db.Exec("DELETE FROM workflows WHERE user_id = ?", user.ID)
+		db.Exec("DELETE FROM tasks WHERE user_id = ?", user.ID)
+		db.Exec("DELETE FROM users WHERE id = ?", user.ID)
+	})
+
+	workflowRepo := repository.NewWorkflowRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+	service := NewWorkflowService(workflowRepo, repository.NewWorkspaceRepository(db))
+
+	statuses := []models.WorkflowStatusInput{
+		{Key: "backlog", Category: models.StatusCategoryTodo},
+		{Key: "review", Category: models.StatusCategoryDoing},
+		{Key: "done", Category: models.StatusCategoryDone},
+	}
+	workflow, err := service.Create(&models.CreateWorkflowRequest{Name: "Review flow", Statuses: statuses}, user.ID)
+	if err != nil {
+		t.Fatalf("creating workflow: %v", err)
+	}
+
+	blocker := &models.Task{Title: "Blocker", UserID: user.ID, Status: models.TaskStatusPending, WorkflowStatus: "backlog", Priority: models.TaskPriorityMedium}
+	blocked := &models.Task{Title: "Blocked", UserID: user.ID, Status: models.TaskStatusInProgress, WorkflowStatus: "review", Priority: models.TaskPriorityMedium}
+	for _, task := range []*models.Task{blocker, blocked} {
+		if err := taskRepo.Create(task); err != nil {
+			t.Fatalf("creating task: %v", err)
+		}
+	}
+	if _, err := taskRepo.AddDependency(blocked.ID, blocker.ID, user.ID); err != nil {
+		t.Fatalf("adding dependency: %v", err)
+	}
+
+	// Moving "review" to done would complete the blocked task
+	recategorized := []models.WorkflowStatusInput{
+		{Key: "backlog", Category: models.StatusCategoryTodo},
+		{Key: "doing", Category: models.StatusCategoryDoing},
+		{Key: "review", Category: models.StatusCategoryDone},
+	}
+	_, err = service.Update(workflow.ID, &models.UpdateWorkflowRequest{Statuses: recategorized}, user.ID)
+	if !errors.Is(err, utils.ErrStatusInUse) {
+		t.Fatalf("Update err = %v, want ErrStatusInUse", err)
+	}
+
+	var stored models.Task
+	if err := db.First(&stored, blocked.ID).Error; err != nil {
+		t.Fatalf("loading task: %v", err)
+	}
+	if stored.Status != models.TaskStatusInProgress || stored.WorkflowStatus != "review" {
+		t.Errorf("blocked task is %s/%s, want in_progress/review", stored.Status, stored.WorkflowStatus)
+	}
+	current, err := service.GetByID(workflow.ID, user.ID)
+	if err != nil {
+		t.Fatalf("loading workflow: %v", err)
+	}
+	if status := current.Status("review"); status == nil || status.Category != models.StatusCategoryDoing {
+		t.Errorf("review status = %+v, want the doing category", status)
+	}
+
+	// The transaction refuses the change too, e.g. when a task moved in after the check
+	next := &models.Workflow{ID: workflow.ID, Name: workflow.Name, UserID: user.ID, Statuses: []models.WorkflowStatus{
+		{Key: "backlog", Category: models.StatusCategoryTodo},
+		{Key: "doing", Category: models.StatusCategoryDoing, Position: 1},
+		{Key: "review", Category: models.StatusCategoryDone, Position: 2},
+	}}
+	if err := statusInUseError(workflowRepo.Update(next)); !errors.Is(err, utils.ErrStatusInUse) {
+		t.Errorf("repository Update err = %v, want ErrStatusInUse", err)
+	}
+
+	// Once the task is in another status the category can change
+	if err := taskRepo.UpdateStatus(blocked.ID, user.ID, models.TaskStatusPending, "backlog"); err != nil {
+		t.Fatalf("moving task: %v", err)
+	}
+	if _, err := service.Update(workflow.ID, &models.UpdateWorkflowRequest{Statuses: recategorized}, user.ID); err != nil {
+		t.Fatalf("Update after moving the task: %v", err)
+	}
+}
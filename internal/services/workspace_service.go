@@ -0,0 +1,398 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/mailer"
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/repository"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+// WorkspaceOptions holds invitation settings
+type WorkspaceOptions struct {
+	InvitationExpiry time.Duration
+	BaseURL          string
+}
+
+type WorkspaceService struct {
+	workspaceRepo *repository.WorkspaceRepository
+	userRepo      *repository.UserRepository
+	mailer        mailer.Mailer
+	opts          WorkspaceOptions
+}
+
+// NewWorkspaceService creates a new workspace service
+func NewWorkspaceService(workspaceRepo *repository.WorkspaceRepository, userRepo *repository.UserRepository, mailer mailer.Mailer, opts WorkspaceOptions) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		mailer:        mailer,
+		opts:          opts,
+	}
+}
+
+// Create creates a workspace owned by the user
+func (s *WorkspaceService) Create(userID uint, req *models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("workspace name is required")
+	}
+
+	workspace := &models.Workspace{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		OwnerID:     userID,
+	}
+	if err := s.workspaceRepo.Create(workspace); err != nil {
+		return nil, errors.New("failed to create workspace")
+	}
+
+	workspace.Role = models.WorkspaceRoleOwner
+	workspace.MemberCount = 1
+	return workspace, nil
+}
+
+// GetAllByUser retrieves the workspaces the user is a member of
+func (s *WorkspaceService) GetAllByUser(userID uint) ([]models.Workspace, error) {
+	return s.workspaceRepo.FindAllByUser(userID)
+}
+
+// GetByID retrieves a workspace with its members
+func (s *WorkspaceService) GetByID(id uint, userID uint) (*models.Workspace, error) {
+	workspace, err := s.workspaceRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrWorkspaceNotFound
+	}
+
+	members, err := s.workspaceRepo.FindMembers(id)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Members = members
+	workspace.MemberCount = int64(len(members))
+
+	return workspace, nil
+}
+
+// Update changes the name or description of a workspace. Requires the owner or admin role.
+func (s *WorkspaceService) Update(id uint, userID uint, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	workspace, err := s.workspaceRepo.FindByID(id, userID)
+	if err != nil {
+		return nil, utils.ErrWorkspaceNotFound
+	}
+	if !workspace.Role.CanManage() {
+		return nil, utils.ErrWorkspaceForbidden
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		workspace.Name = name
+	}
+	if req.Description != "" {
+		workspace.Description = strings.TrimSpace(req.Description)
+	}
+
+	if err := s.workspaceRepo.Update(workspace); err != nil {
+		return nil, errors.New("failed to update workspace")
+	}
+
+	return workspace, nil
+}
+
+// Delete deletes a workspace together with its tasks and categories. Only the owner can delete it.
+func (s *WorkspaceService) Delete(id uint, userID uint) error {
+	workspace, err := s.workspaceRepo.FindByID(id, userID)
+	if err != nil {
+		return utils.ErrWorkspaceNotFound
+	}
+	if workspace.Role != models.WorkspaceRoleOwner {
+		return utils.ErrWorkspaceForbidden
+	}
+
+	return s.workspaceRepo.Delete(id)
+}
+
+// GetMembers retrieves the members of a workspace
+func (s *WorkspaceService) GetMembers(id uint, userID uint) ([]models.WorkspaceMember, error) {
+	if _, err := s.workspaceRepo.FindByID(id, userID); err != nil {
+		return nil, utils.ErrWorkspaceNotFound
+	}
+	return s.workspaceRepo.FindMembers(id)
+}
+
+// UpdateMemberRole changes the role of another member. Owners and admins can change
+// members and viewers; only the owner can change admins or transfer ownership.
+func (s *WorkspaceService) UpdateMemberRole(id uint, actorID uint, memberID uint, role models.WorkspaceRole) (*models.WorkspaceMember, error) {
+	actorRole, target, err := s.authorizeMemberChange(id, actorID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if memberID == actorID {
+		return nil, utils.ErrWorkspaceForbidden
+	}
+
+	if role == models.WorkspaceRoleOwner {
+		if actorRole != models.WorkspaceRoleOwner {
+			return nil, utils.ErrWorkspaceForbidden
+		}
+		if err := s.workspaceRepo.TransferOwnership(id, actorID, memberID); err != nil {
+			return nil, err
+		}
+	} else if err := s.workspaceRepo.UpdateMemberRole(id, memberID, role); err != nil {
+		return nil, err
+	}
+
+	target.Role = role
+	return target, nil
+}
+
+// RemoveMember removes a member from a workspace. Members can always remove themselves,
+// except the owner who has to transfer ownership first.
+func (s *WorkspaceService) RemoveMember(id uint, actorID uint, memberID uint) error {
+	if memberID == actorID {
+		role, err := s.workspaceRepo.FindRole(id, actorID)
+		if err != nil {
+			return utils.ErrWorkspaceNotFound
+		}
+		if role == models.WorkspaceRoleOwner {
+			return utils.ErrOwnerCannotLeave
+		}
+		return s.workspaceRepo.RemoveMember(id, actorID)
+	}
+
+	if _, _, err := s.authorizeMemberChange(id, actorID, memberID); err != nil {
+		return err
+	}
+	return s.workspaceRepo.RemoveMember(id, memberID)
+}
+
+// authorizeMemberChange checks that the actor may change or remove the member
+func (s *WorkspaceService) authorizeMemberChange(id uint, actorID uint, memberID uint) (models.WorkspaceRole, *models.WorkspaceMember, error) {
+	actorRole, err := s.workspaceRepo.FindRole(id, actorID)
+	if err != nil {
+		return "", nil, utils.ErrWorkspaceNotFound
+	}
+	if !actorRole.CanManage() {
+		return "", nil, utils.ErrWorkspaceForbidden
+	}
+
+	target, err := s.workspaceRepo.FindMember(id, memberID)
+	if err != nil {
+		return "", nil, utils.ErrMemberNotFound
+	}
+	if target.Role == models.WorkspaceRoleOwner {
+		return "", nil, utils.ErrWorkspaceForbidden
+	}
+	if target.Role == models.WorkspaceRoleAdmin && actorRole != models.WorkspaceRoleOwner {
+		return "", nil, utils.ErrWorkspaceForbidden
+	}
+
+	return actorRole, target, nil
+}
+
+// CreateInvitation invites someone to the workspace. With an email the invitation is sent to
+// that address and can be used once; without one a shareable link is created. The token is
+// only returned here.
+func (s *WorkspaceService) CreateInvitation(id uint, actorID uint, req *models.CreateInvitationRequest) (*models.WorkspaceInvitation, error) {
+	workspace, err := s.workspaceRepo.FindByID(id, actorID)
+	if err != nil {
+		return nil, utils.ErrWorkspaceNotFound
+	}
+	if !workspace.Role.CanManage() {
+		return nil, utils.ErrWorkspaceForbidden
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.WorkspaceRoleMember
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email != "" {
+		if user, err := s.userRepo.FindByEmail(email); err == nil {
+			isMember, err := s.workspaceRepo.IsMember(id, user.ID)
+			if err != nil {
+				return nil, err
+			}
+			if isMember {
+				return nil, utils.ErrAlreadyMember
+			}
+		}
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	invitation := &models.WorkspaceInvitation{
+		WorkspaceID: id,
+		Email:       email,
+		Role:        role,
+		TokenHash:   utils.HashToken(rawToken),
+		InvitedByID: actorID,
+		ExpiresAt:   time.Now().Add(s.opts.InvitationExpiry),
+	}
+	if err := s.workspaceRepo.CreateInvitation(invitation); err != nil {
+		return nil, errors.New("failed to create invitation")
+	}
+
+	invitation.Token = rawToken
+	invitation.InviteURL = fmt.Sprintf("%s/accept-invitation?token=%s", s.opts.BaseURL, rawToken)
+
+	if email != "" {
+		s.sendInvitationEmail(workspace, invitation, actorID)
+	}
+
+	return invitation, nil
+}
+
+// GetInvitations retrieves the pending invitations of a workspace
+func (s *WorkspaceService) GetInvitations(id uint, actorID uint) ([]models.WorkspaceInvitation, error) {
+	role, err := s.workspaceRepo.FindRole(id, actorID)
+	if err != nil {
+		return nil, utils.ErrWorkspaceNotFound
+	}
+	if !role.CanManage() {
+		return nil, utils.ErrWorkspaceForbidden
+	}
+	return s.workspaceRepo.FindInvitations(id)
+}
+
+// RevokeInvitation revokes a pending invitation
+func (s *WorkspaceService) RevokeInvitation(id uint, actorID uint, invitationID uint) error {
+	role, err := s.workspaceRepo.FindRole(id, actorID)
+	if err != nil {
+		return utils.ErrWorkspaceNotFound
+	}
+	if !role.CanManage() {
+		return utils.ErrWorkspaceForbidden
+	}
+
+	if err := s.workspaceRepo.RevokeInvitation(invitationID, id); err != nil {
+		return utils.ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation adds the user to the workspace of the invitation
+func (s *WorkspaceService) AcceptInvitation(userID uint, req *models.AcceptInvitationRequest) (*models.Workspace, error) {
+	invitation, err := s.workspaceRepo.FindInvitationByTokenHash(utils.HashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		return nil, utils.ErrInvalidInvitation
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if err := checkInvitationRecipient(invitation, user); err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.workspaceRepo.IsMember(invitation.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, utils.ErrAlreadyMember
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	}
+	if err := s.workspaceRepo.AcceptInvitation(invitation, member); err != nil {
+		// Lost a race with another use of a single-use invitation
+		if err.Error() == "invitation not found" {
+			return nil, utils.ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	return s.GetByID(invitation.WorkspaceID, userID)
+}
+
+// checkInvitationRecipient checks that a user may accept an invitation. Invitations sent to an
+// email address need an account that has verified that address; links work for anyone.
+func checkInvitationRecipient(invitation *models.WorkspaceInvitation, user *models.User) error {
+	if invitation.IsLink() {
+		return nil
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return utils.ErrInvitationEmailMismatch
+	}
+	if !user.IsEmailVerified() {
+		return utils.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// sendInvitationEmail emails an invitation link
+func (s *WorkspaceService) sendInvitationEmail(workspace *models.Workspace, invitation *models.WorkspaceInvitation, inviterID uint) {
+	inviter := "A teammate"
+	if user, err := s.userRepo.FindByID(inviterID); err == nil {
+		inviter = user.FullName
+	}
+
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You have been invited to %s", workspace.Name),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to join the workspace %q as %s. Accept the invitation with the link below:\n\n%s\n\nInvitation token: %s\n\nYou need an account with this email address. This invitation expires at %s.\n",
+			inviter, workspace.Name, invitation.Role, invitation.InviteURL, invitation.Token,
+			invitation.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("❌ Failed to send workspace invitation for workspace %d: %v", workspace.ID, err)
+	}
+}
+
+// requireWorkspaceEditor checks that the user may change tasks and categories of a workspace.
+// A nil workspaceID stands for the user's personal tasks and categories.
+func requireWorkspaceEditor(workspaceRepo *repository.WorkspaceRepository, workspaceID *uint, userID uint) error {
+	if workspaceID == nil {
+		return nil
+	}
+
+	role, err := workspaceRepo.FindRole(*workspaceID, userID)
+	if err != nil {
+		return utils.ErrWorkspaceNotFound
+	}
+	if !role.CanEdit() {
+		return utils.ErrWorkspaceForbidden
+	}
+	return nil
+}
+
+// requireWorkspaceManager checks that the user may manage a workspace, i.e. is its owner or an
+// admin. Personal records (nil workspace) are always manageable by their owner.
+func requireWorkspaceManager(workspaceRepo *repository.WorkspaceRepository, workspaceID *uint, userID uint) error {
+	if workspaceID == nil {
+		return nil
+	}
+
+	role, err := workspaceRepo.FindRole(*workspaceID, userID)
+	if err != nil {
+		return utils.ErrWorkspaceNotFound
+	}
+	if !role.CanManage() {
+		return utils.ErrWorkspaceForbidden
+	}
+	return nil
+}
+
+// requireEditableTask checks that a task is visible to the user and that the user may change it
+func requireEditableTask(taskRepo *repository.TaskRepository, workspaceRepo *repository.WorkspaceRepository, taskID uint, userID uint) error {
+	task, err := taskRepo.FindByID(taskID, userID)
+	if err != nil {
+		return utils.ErrTaskNotFound
+	}
+	return requireWorkspaceEditor(workspaceRepo, task.WorkspaceID, userID)
+}
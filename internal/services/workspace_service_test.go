@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/models"
+	"github.com/hoanghnt/TaskManagementAPI/internal/utils"
+)
+
+func TestCheckInvitationRecipient(t *testing.T) {
+	verifiedAt := time.Now()
+	emailInvitation := &models.WorkspaceInvitation{Email: "invitee@example.com"}
+	linkInvitation := &models.WorkspaceInvitation{}
+
+	tests := []struct {
+		name       string
+		invitation *models.WorkspaceInvitation
+		user       *models.User
+		want       error
+	}{
+		{"verified account with the invited email", emailInvitation, &models.User{Email: "invitee@example.com", EmailVerifiedAt: &verifiedAt}, nil},
+		{"email is compared without case", emailInvitation, &models.User{Email: "Invitee@Example.com", EmailVerifiedAt: &verifiedAt}, nil},
+		{"unverified account with the invited email", emailInvitation, &models.User{Email: "invitee@example.com"}, utils.ErrEmailNotVerified},
+		{"verified account with another email", emailInvitation, &models.User{Email: "other@example.com", EmailVerifiedAt: &verifiedAt}, utils.ErrInvitationEmailMismatch},
+		{"unverified account with another email", emailInvitation, &models.User{Email: "other@example.com"}, utils.ErrInvitationEmailMismatch},
+		{"link invitation for an unverified account", linkInvitation, &models.User{Email: "anyone@example.com"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkInvitationRecipient(tt.invitation, tt.user)
+			if tt.want == nil && err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(filepath.Join(dir, "blobs"))
+	ctx := context.Background()
+	key := "tasks/12/Quarterly report é.pdf"
+	content := []byte("%PDF-1.7 test content")
+
+	if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("reading blob: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get returned %q, want %q", got, content)
+	}
+
+	// Replacing a blob overwrites its content
+	replacement := []byte("v2")
+	if err := store.Put(ctx, key, bytes.NewReader(replacement), int64(len(replacement)), ""); err != nil {
+		t.Fatalf("Put replacement: %v", err)
+	}
+	rc, err = store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get replacement: %v", err)
+	}
+	got, _ = io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, replacement) {
+		t.Errorf("Get after replace returned %q, want %q", got, replacement)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	// Deleting a missing blob is not an error
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of missing blob: %v", err)
+	}
+}
+
+func TestLocalStoreGetMissing(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	if _, err := store.Get(context.Background(), "tasks/1/missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoreSizeMismatch(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "tasks/1/short", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Fatal("Put with a short body succeeded")
+	}
+	if _, err := store.Get(ctx, "tasks/1/short"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after failed Put: err = %v, want ErrNotFound", err)
+	}
+	// The temporary upload file is removed
+	entries, err := os.ReadDir(filepath.Join(dir, "tasks", "1"))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("directory still contains %d entries after a failed Put", len(entries))
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	for _, key := range []string{"", "/", "../escape", "tasks/../../escape", "/absolute", "tasks//double", "tasks/1/", `tasks\1`} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q): err = %v, want an invalid key error", key, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hoanghnt/TaskManagementAPI/internal/config"
+)
+
+// ErrNotFound means no blob is stored under the key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores file contents under keys chosen by the caller. Keys are slash separated
+// paths such as "tasks/12/3f9a...".
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing an existing blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates a blob store for the configured driver
+func New(cfg *config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local", "":
+		return NewLocalStore(cfg.LocalDir), nil
+	case "s3":
+		return NewS3Store(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
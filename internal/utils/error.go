@@ -15,6 +15,139 @@ var (
 	// Task & Category specific errors
 	ErrCategoryNotFound = errors.New("category not found")
 	ErrTaskNotFound     = errors.New("task not found")
+
+	// Session specific errors
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+
+	// Password specific errors
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged = errors.New("new password must be different from the current password")
+
+	// Email verification specific errors
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+
+	// Account specific errors
+	ErrUserNotFound     = errors.New("user not found")
+	ErrAccountDisabled  = errors.New("account is disabled")
+	ErrCannotModifySelf = errors.New("admins cannot disable or demote their own account")
+
+	// API key specific errors
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+	ErrInvalidAPIKey     = errors.New("invalid, expired or revoked api key")
+	ErrInsufficientScope = errors.New("api key does not have the required scope")
+	ErrAPIKeyNotAllowed  = errors.New("this endpoint cannot be used with an api key")
+
+	// Two-factor specific errors
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication setup has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrInvalidChallenge        = errors.New("invalid or expired login challenge")
+
+	// Account lockout specific errors
+	ErrInvalidUnlockToken = errors.New("invalid or expired unlock token")
+
+	// Single sign-on specific errors
+	ErrOIDCNotConfigured    = errors.New("single sign-on is not configured")
+	ErrInvalidOIDCState     = errors.New("invalid or expired login state")
+	ErrOIDCLoginFailed      = errors.New("single sign-on login failed")
+	ErrOIDCUnavailable      = errors.New("identity provider is unavailable")
+	ErrOIDCEmailNotVerified = errors.New("the identity provider did not confirm the email address")
+	ErrOIDCSignupDisabled   = errors.New("no account exists for this email address")
+
+	// Data export specific errors
+	ErrDataExportNotFound   = errors.New("data export not found")
+	ErrDataExportInProgress = errors.New("a data export is already being generated")
+	ErrDataExportNotReady   = errors.New("data export is not available for download")
+	ErrInvalidDownloadToken = errors.New("invalid or expired download link")
+
+	// Workspace specific errors
+	ErrWorkspaceNotFound         = errors.New("workspace not found")
+	ErrWorkspaceForbidden        = errors.New("insufficient workspace permissions")
+	ErrMemberNotFound            = errors.New("workspace member not found")
+	ErrAlreadyMember             = errors.New("user is already a member of this workspace")
+	ErrOwnerCannotLeave          = errors.New("the workspace owner must transfer ownership before leaving")
+	ErrOwnsSharedWorkspaces      = errors.New("transfer or delete workspaces shared with other members first")
+	ErrInvitationNotFound        = errors.New("invitation not found")
+	ErrInvalidInvitation         = errors.New("invalid or expired invitation")
+	ErrInvitationEmailMismatch   = errors.New("invitation was sent to a different email address")
+	ErrCategoryWorkspaceMismatch = errors.New("category belongs to a different workspace")
+
+	// Task assignment specific errors
+	ErrAssigneeNoAccess      = errors.New("assignee cannot access this task")
+	ErrAssigneeNotFound      = errors.New("user is not assigned to this task")
+	ErrInvalidAssigneeFilter = errors.New("assignee must be me, none or a user ID")
+
+	// Subtask specific errors
+	ErrParentTaskNotFound      = errors.New("parent task not found")
+	ErrParentWorkspaceMismatch = errors.New("parent task belongs to a different workspace")
+	ErrTaskHierarchyCycle      = errors.New("a task cannot be moved below itself or one of its subtasks")
+	ErrTaskDepthExceeded       = errors.New("subtasks are nested too deeply")
+	ErrOpenSubtasks            = errors.New("task has open subtasks")
+	ErrTaskHasSubtasks         = errors.New("task has subtasks")
+
+	// Task dependency specific errors
+	ErrBlockerNotFound             = errors.New("blocking task not found")
+	ErrDependencyNotFound          = errors.New("task is not blocked by this task")
+	ErrDependencyCycle             = errors.New("dependency would create a cycle")
+	ErrDependencyWorkspaceMismatch = errors.New("blocking task belongs to a different workspace")
+	ErrTaskBlocked                 = errors.New("task is blocked by open tasks")
+
+	// Recurring task specific errors
+	ErrInvalidRecurrenceRule  = errors.New("invalid recurrence rule")
+	ErrRecurrenceNeedsDueDate = errors.New("recurring tasks need a due date")
+	ErrSeriesNotFound         = errors.New("task series not found")
+
+	// Tag specific errors
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagNameTaken    = errors.New("tag with this name already exists")
+	ErrTagNameRequired = errors.New("tag name is required")
+
+	// Attachment specific errors
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentRequired       = errors.New("file is required")
+	ErrAttachmentEmpty          = errors.New("file is empty")
+	ErrAttachmentTooLarge       = errors.New("file exceeds the maximum attachment size")
+	ErrAttachmentTypeNotAllowed = errors.New("file type is not allowed")
+	ErrAttachmentQuotaExceeded  = errors.New("attachment storage quota exceeded")
+
+	// Checklist specific errors
+	ErrChecklistItemNotFound  = errors.New("checklist item not found")
+	ErrChecklistItemEmpty     = errors.New("checklist item text is required")
+	ErrChecklistFull          = errors.New("checklist has reached the maximum number of items")
+	ErrChecklistOrderMismatch = errors.New("item_ids must list every checklist item of the task exactly once")
+
+	// Time tracking specific errors
+	ErrTimeEntryNotFound   = errors.New("time entry not found")
+	ErrTimerAlreadyRunning = errors.New("a timer is already running, stop it first")
+	ErrTimerNotRunning     = errors.New("no timer is running on this task")
+	ErrTimeEntryRunning    = errors.New("a running timer has no end yet, stop it before changing its times")
+	ErrTimeEntryOverlap    = errors.New("time entry overlaps another time entry of the user")
+	ErrInvalidTimeEntry    = errors.New("ended_at must be after started_at")
+	ErrTimeEntryInFuture   = errors.New("time entries cannot end in the future")
+	ErrNotTimeEntryOwner   = errors.New("only the owner can change a time entry")
+	ErrInvalidReportRange  = errors.New("from must not be after to and the range cannot exceed 366 days")
+
+	// Workflow specific errors
+	ErrWorkflowNotFound       = errors.New("workflow not found")
+	ErrWorkflowExists         = errors.New("a workflow already exists for this workspace or for your personal tasks")
+	ErrInvalidWorkflow        = errors.New("invalid workflow")
+	ErrUnknownStatus          = errors.New("status is not part of the task's workflow")
+	ErrIllegalTransition      = errors.New("the workflow does not allow this status change")
+	ErrStatusCategoryMismatch = errors.New("status belongs to different categories in the workflows of the selected tasks")
+	ErrStatusInUse            = errors.New("status still holds tasks and cannot change its category; move them to another status first")
+
+	// Comment specific errors
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("only the author can change a comment")
+
+	// Notification specific errors
+	ErrNotificationNotFound = errors.New("notification not found")
 )
 
 // IsNotFoundError checks if error is not found error
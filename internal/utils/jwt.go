@@ -2,36 +2,107 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret []byte
+var (
+	jwtSecret        []byte
+	signingKey       *jwtKey
+	verificationKeys map[string]*jwtKey
+)
 
-// InitJWT initializes JWT secret
+// InitJWT initializes HS256 signing with a shared secret
 func InitJWT(secret string) {
 	jwtSecret = []byte(secret)
+	signingKey = nil
+	verificationKeys = nil
+}
+
+// InitJWTKeys initializes asymmetric signing with a PEM private key. The algorithm
+// (RS256 or EdDSA) follows from the key type and tokens carry the key ID in the kid header.
+// Tokens signed with the signing key or any of the verification keys are accepted,
+// which allows keys to be rotated without invalidating tokens that are still in flight.
+func InitJWTKeys(signingKeyFile string, verificationKeyFiles []string) error {
+	key, err := loadPrivateKeyFile(signingKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load JWT signing key: %w", err)
+	}
+
+	keys := map[string]*jwtKey{key.id: key}
+	for _, path := range verificationKeyFiles {
+		verificationKey, err := loadPublicKeyFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load JWT verification key: %w", err)
+		}
+		if _, exists := keys[verificationKey.id]; !exists {
+			keys[verificationKey.id] = verificationKey
+		}
+	}
+
+	jwtSecret = nil
+	signingKey = key
+	verificationKeys = keys
+	return nil
+}
+
+// JWTAlgorithm returns the algorithm used to sign new tokens
+func JWTAlgorithm() string {
+	if signingKey != nil {
+		return signingKey.method.Alg()
+	}
+	return jwt.SigningMethodHS256.Alg()
+}
+
+// JWKS returns the public verification keys. It is empty when HS256 is used
+// because a shared secret cannot be published.
+func JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if signingKey == nil {
+		return set
+	}
+
+	// Current signing key first so clients that only look at the first key keep working
+	set.Keys = append(set.Keys, signingKey.jwk())
+
+	ids := make([]string, 0, len(verificationKeys))
+	for id := range verificationKeys {
+		if id != signingKey.id {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		set.Keys = append(set.Keys, verificationKeys[id].jwk())
+	}
+	return set
 }
 
 // JWTClaims represents the claims in JWT token
 type JWTClaims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(userID uint, username string, expiryHours int) (string, error) {
-	if len(jwtSecret) == 0 {
+// GenerateToken generates a new short-lived JWT access token bound to a session
+func GenerateToken(userID uint, username string, role string, sessionID string, expiry time.Duration) (string, error) {
+	if signingKey == nil && len(jwtSecret) == 0 {
 		return "", errors.New("JWT secret not initialized")
 	}
 
-	expirationTime := time.Now().Add(time.Duration(expiryHours) * time.Hour)
+	expirationTime := time.Now().Add(expiry)
 
 	claims := &JWTClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -39,6 +110,12 @@ func GenerateToken(userID uint, username string, expiryHours int) (string, error
 		},
 	}
 
+	if signingKey != nil {
+		token := jwt.NewWithClaims(signingKey.method, claims)
+		token.Header["kid"] = signingKey.id
+		return token.SignedString(signingKey.private)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(jwtSecret)
 	if err != nil {
@@ -50,13 +127,17 @@ func GenerateToken(userID uint, username string, expiryHours int) (string, error
 
 // ValidateToken validates JWT token and returns claims
 func ValidateToken(tokenString string) (*JWTClaims, error) {
-	if len(jwtSecret) == 0 {
+	if signingKey == nil && len(jwtSecret) == 0 {
 		return nil, errors.New("JWT secret not initialized")
 	}
 
 	claims := &JWTClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if signingKey != nil {
+			return verificationKey(token)
+		}
+
 		// Verify signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
@@ -75,6 +156,20 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 	return claims, nil
 }
 
+// verificationKey looks up the public key named by the kid header and checks
+// that the token was signed with the algorithm of that key
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := verificationKeys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.public, nil
+}
+
 // ExtractTokenFromHeader extracts token from Authorization header
 func ExtractTokenFromHeader(authHeader string) (string, error) {
 	if authHeader == "" {
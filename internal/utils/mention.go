@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @username when the @ does not follow a word character,
+// so email addresses are not taken for mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.\-]+)`)
+
+// ParseMentions returns the distinct usernames mentioned with @username in a text,
+// in order of appearance. Trailing dots and dashes are treated as punctuation.
+func ParseMentions(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
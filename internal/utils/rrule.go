@@ -0,0 +1,374 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies supported by RRule
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+)
+
+// maxRecurrencePeriods bounds the search for the next occurrence, e.g. rules that
+// only match the 31st of February would otherwise never end
+const maxRecurrencePeriods = 50000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RRule is a recurrence rule as defined by RFC 5545, limited to the DAILY, WEEKLY and
+// MONTHLY frequencies with the INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL parts.
+// Weeks start on Monday.
+type RRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []RRuleDay
+	ByMonthDay []int
+	Count      int        // 0 when the number of occurrences is not limited
+	Until      *time.Time // last possible occurrence, inclusive
+}
+
+// RRuleDay is a BYDAY entry. Ordinal is only used by monthly rules: 1 for the first
+// weekday of the month, -1 for the last and 0 for every one.
+type RRuleDay struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// ParseRRule parses a rule such as "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10". A leading
+// "RRULE:" is accepted. The error wraps ErrInvalidRecurrenceRule.
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || val == "" {
+			return nil, invalidRRule("%q is not a NAME=VALUE pair", part)
+		}
+		if _, exists := parts[key]; exists {
+			return nil, invalidRRule("%s is given more than once", key)
+		}
+		parts[key] = strings.ToUpper(strings.TrimSpace(val))
+	}
+
+	rule := &RRule{Freq: parts["FREQ"], Interval: 1}
+	switch rule.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	case "":
+		return nil, invalidRRule("FREQ is required")
+	default:
+		return nil, invalidRRule("FREQ must be DAILY, WEEKLY or MONTHLY")
+	}
+
+	for key, val := range parts {
+		var err error
+		switch key {
+		case "FREQ":
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, val, 1000)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, val, 1000)
+		case "UNTIL":
+			rule.Until, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val, rule.Freq == FrequencyMonthly)
+		case "BYMONTHDAY":
+			if rule.Freq != FrequencyMonthly {
+				return nil, invalidRRule("BYMONTHDAY is only supported with FREQ=MONTHLY")
+			}
+			rule.ByMonthDay, err = parseByMonthDay(val)
+		default:
+			return nil, invalidRRule("%s is not supported", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, invalidRRule("COUNT and UNTIL cannot be combined")
+	}
+	return rule, nil
+}
+
+// String formats the rule in a canonical form
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// String formats the entry as in BYDAY, e.g. "MO" or "-1FR"
+func (d RRuleDay) String() string {
+	for code, weekday := range rruleWeekdays {
+		if weekday == d.Weekday {
+			if d.Ordinal != 0 {
+				return strconv.Itoa(d.Ordinal) + code
+			}
+			return code
+		}
+	}
+	return ""
+}
+
+// Next returns the first occurrence after the given time of a series that starts at dtstart.
+// Like in RFC 5545, dtstart is always the first occurrence and COUNT includes it. ok is false
+// when the series has ended.
+func (r *RRule) Next(dtstart, after time.Time) (next time.Time, ok bool) {
+	occurrences := 1
+	if dtstart.After(after) {
+		return dtstart, true
+	}
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, candidate := range r.candidates(dtstart, period) {
+			if !candidate.After(dtstart) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			occurrences++
+			if r.Count > 0 && occurrences > r.Count {
+				return time.Time{}, false
+			}
+			if candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// candidates returns the sorted occurrences of the given period (day, week or month) after dtstart
+// before checking them against dtstart, COUNT and UNTIL
+func (r *RRule) candidates(dtstart time.Time, period int) []time.Time {
+	year, month, day := dtstart.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	switch r.Freq {
+	case FrequencyDaily:
+		candidate := at(year, month, day+period*r.Interval)
+		if len(r.ByDay) > 0 && !r.matchesWeekday(candidate.Weekday()) {
+			return nil
+		}
+		return []time.Time{candidate}
+
+	case FrequencyWeekly:
+		weekdays := []time.Weekday{dtstart.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, byDay := range r.ByDay {
+				weekdays = append(weekdays, byDay.Weekday)
+			}
+		}
+		monday := day - daysSinceMonday(dtstart.Weekday()) + 7*period*r.Interval
+		var candidates []time.Time
+		for _, weekday := range weekdays {
+			candidates = append(candidates, at(year, month, monday+daysSinceMonday(weekday)))
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		return candidates
+
+	default:
+		first := at(year, month+time.Month(period*r.Interval), 1)
+		daysInMonth := first.AddDate(0, 1, -1).Day()
+
+		matches := make(map[int]bool)
+		switch {
+		case len(r.ByMonthDay) > 0:
+			for _, monthDay := range r.ByMonthDay {
+				if monthDay < 0 {
+					monthDay = daysInMonth + monthDay + 1
+				}
+				if monthDay >= 1 && monthDay <= daysInMonth {
+					matches[monthDay] = true
+				}
+			}
+			if len(r.ByDay) > 0 {
+				for monthDay := range matches {
+					if !r.matchesMonthWeekday(first, monthDay, daysInMonth) {
+						delete(matches, monthDay)
+					}
+				}
+			}
+		case len(r.ByDay) > 0:
+			for monthDay := 1; monthDay <= daysInMonth; monthDay++ {
+				if r.matchesMonthWeekday(first, monthDay, daysInMonth) {
+					matches[monthDay] = true
+				}
+			}
+		default:
+			if day <= daysInMonth {
+				matches[day] = true
+			}
+		}
+
+		monthDays := make([]int, 0, len(matches))
+		for monthDay := range matches {
+			monthDays = append(monthDays, monthDay)
+		}
+		sort.Ints(monthDays)
+
+		candidates := make([]time.Time, len(monthDays))
+		for i, monthDay := range monthDays {
+			candidates[i] = at(first.Year(), first.Month(), monthDay)
+		}
+		return candidates
+	}
+}
+
+// matchesWeekday checks a weekday against BYDAY entries without ordinal
+func (r *RRule) matchesWeekday(weekday time.Weekday) bool {
+	for _, byDay := range r.ByDay {
+		if byDay.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthWeekday checks a day of the month against the BYDAY entries of a monthly rule
+func (r *RRule) matchesMonthWeekday(first time.Time, monthDay, daysInMonth int) bool {
+	weekday := time.Weekday((int(first.Weekday()) + monthDay - 1) % 7)
+	for _, byDay := range r.ByDay {
+		if byDay.Weekday != weekday {
+			continue
+		}
+		switch {
+		case byDay.Ordinal == 0:
+			return true
+		case byDay.Ordinal > 0 && (monthDay-1)/7+1 == byDay.Ordinal:
+			return true
+		case byDay.Ordinal < 0 && (daysInMonth-monthDay)/7+1 == -byDay.Ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+// daysSinceMonday counts the days from Monday to a weekday of the same week
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func parsePositive(key, value string, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, invalidRRule("%s must be a number between 1 and %d", key, max)
+	}
+	return n, nil
+}
+
+// parseUntil accepts a date (the whole day is included) or a UTC date-time
+func parseUntil(value string) (*time.Time, error) {
+	if until, err := time.Parse("20060102", value); err == nil {
+		until = until.Add(24*time.Hour - time.Second)
+		return &until, nil
+	}
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return &until, nil
+		}
+	}
+	return nil, invalidRRule("UNTIL must be a date (20060102) or a UTC date-time (20060102T150405Z)")
+}
+
+func parseByDay(value string, allowOrdinal bool) ([]RRuleDay, error) {
+	var days []RRuleDay
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, invalidRRule("%q is not a weekday", item)
+		}
+		weekday, ok := rruleWeekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, invalidRRule("%q is not a weekday", item)
+		}
+
+		day := RRuleDay{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			if !allowOrdinal {
+				return nil, invalidRRule("BYDAY ordinals like %q are only supported with FREQ=MONTHLY", item)
+			}
+			ordinal, err := strconv.Atoi(prefix)
+			if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, invalidRRule("%q has an invalid ordinal", item)
+			}
+			day.Ordinal = ordinal
+		}
+		if !containsDay(days, day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func containsDay(days []RRuleDay, day RRuleDay) bool {
+	for _, existing := range days {
+		if existing == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, invalidRRule("%q is not a day of the month", item)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func invalidRRule(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRecurrenceRule, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,314 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// utcDate returns a time in UTC; rules keep the time of day of dtstart
+func utcDate(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// occurrences lists the first n occurrences of a series by following Next from dtstart.
+// It stops early when the series ends.
+func occurrences(rule *RRule, dtstart time.Time, n int) []time.Time {
+	var result []time.Time
+	after := dtstart.Add(-time.Second)
+	for len(result) < n {
+		next, ok := rule.Next(dtstart, after)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		after = next
+	}
+	return result
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"rrule:freq=weekly;byday=th,mo,mo;interval=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,MO"},
+		{" FREQ=MONTHLY;COUNT=5;BYDAY=-1FR;INTERVAL=3; ", "FREQ=MONTHLY;INTERVAL=3;BYDAY=-1FR;COUNT=5"},
+		{"FREQ=MONTHLY;BYDAY=+2TU", "FREQ=MONTHLY;BYDAY=2TU"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{"FREQ=WEEKLY;UNTIL=20240131", "FREQ=WEEKLY;UNTIL=20240131T235959Z"},
+		{"FREQ=DAILY;UNTIL=20240131T120000Z", "FREQ=DAILY;UNTIL=20240131T120000Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := ParseRRule(tt.value)
+			if err != nil {
+				t.Fatalf("ParseRRule: %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRRuleRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"missing FREQ", "INTERVAL=2"},
+		{"unsupported FREQ", "FREQ=YEARLY"},
+		{"part without value", "FREQ=DAILY;COUNT"},
+		{"part without equals sign", "FREQ=DAILY;BYDAY"},
+		{"repeated part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"unsupported part", "FREQ=DAILY;BYSETPOS=1"},
+		{"zero INTERVAL", "FREQ=DAILY;INTERVAL=0"},
+		{"INTERVAL too large", "FREQ=DAILY;INTERVAL=1001"},
+		{"non-numeric COUNT", "FREQ=DAILY;COUNT=ten"},
+		{"negative COUNT", "FREQ=DAILY;COUNT=-1"},
+		{"COUNT with UNTIL", "FREQ=DAILY;COUNT=2;UNTIL=20240101"},
+		{"malformed UNTIL", "FREQ=DAILY;UNTIL=2024-01-01"},
+		{"unknown weekday", "FREQ=WEEKLY;BYDAY=XX"},
+		{"short weekday", "FREQ=WEEKLY;BYDAY=M"},
+		{"ordinal on weekly rule", "FREQ=WEEKLY;BYDAY=1MO"},
+		{"ordinal on daily rule", "FREQ=DAILY;BYDAY=-1FR"},
+		{"zero ordinal", "FREQ=MONTHLY;BYDAY=0MO"},
+		{"ordinal too large", "FREQ=MONTHLY;BYDAY=6MO"},
+		{"non-numeric ordinal", "FREQ=MONTHLY;BYDAY=XMO"},
+		{"BYMONTHDAY on weekly rule", "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{"zero BYMONTHDAY", "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{"BYMONTHDAY too large", "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{"BYMONTHDAY too small", "FREQ=MONTHLY;BYMONTHDAY=-32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.value)
+			if !errors.Is(err, ErrInvalidRecurrenceRule) {
+				t.Errorf("ParseRRule(%q) = %v, %v; want ErrInvalidRecurrenceRule", tt.value, rule, err)
+			}
+		})
+	}
+}
+
+func TestRRuleOccurrences(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "daily with interval and count",
+			rule:    "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			dtstart: utcDate(2024, 1, 1, 9),
+			want:    []time.Time{utcDate(2024, 1, 1, 9), utcDate(2024, 1, 3, 9), utcDate(2024, 1, 5, 9)},
+		},
+		{
+			name:    "daily on weekdays only",
+			rule:    "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=4",
+			dtstart: utcDate(2024, 1, 4, 9), // Thursday
+			want:    []time.Time{utcDate(2024, 1, 4, 9), utcDate(2024, 1, 5, 9), utcDate(2024, 1, 8, 9), utcDate(2024, 1, 9, 9)},
+		},
+		{
+			name:    "daily until a date includes that whole day",
+			rule:    "FREQ=DAILY;UNTIL=20240103",
+			dtstart: utcDate(2024, 1, 1, 23),
+			want:    []time.Time{utcDate(2024, 1, 1, 23), utcDate(2024, 1, 2, 23), utcDate(2024, 1, 3, 23)},
+		},
+		{
+			name:    "weekly on the weekday of dtstart",
+			rule:    "FREQ=WEEKLY;COUNT=3",
+			dtstart: utcDate(2024, 2, 28, 9), // Wednesday, crosses the leap day
+			want:    []time.Time{utcDate(2024, 2, 28, 9), utcDate(2024, 3, 6, 9), utcDate(2024, 3, 13, 9)},
+		},
+		{
+			name:    "weekly on several days",
+			rule:    "FREQ=WEEKLY;BYDAY=TH,MO",
+			dtstart: utcDate(2024, 1, 1, 9), // Monday
+			want:    []time.Time{utcDate(2024, 1, 1, 9), utcDate(2024, 1, 4, 9), utcDate(2024, 1, 8, 9), utcDate(2024, 1, 11, 9)},
+		},
+		{
+			name:    "weekly starting between the days of a week",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3",
+			dtstart: utcDate(2024, 1, 3, 9), // Wednesday, which does not match itself
+			want:    []time.Time{utcDate(2024, 1, 3, 9), utcDate(2024, 1, 5, 9), utcDate(2024, 1, 8, 9)},
+		},
+		{
+			name:    "every other week until a date",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;UNTIL=20240131",
+			dtstart: utcDate(2024, 1, 2, 9),
+			want:    []time.Time{utcDate(2024, 1, 2, 9), utcDate(2024, 1, 16, 9), utcDate(2024, 1, 30, 9)},
+		},
+		{
+			name:    "monthly on the second Tuesday",
+			rule:    "FREQ=MONTHLY;BYDAY=2TU",
+			dtstart: utcDate(2024, 1, 9, 9),
+			want:    []time.Time{utcDate(2024, 1, 9, 9), utcDate(2024, 2, 13, 9), utcDate(2024, 3, 12, 9), utcDate(2024, 4, 9, 9)},
+		},
+		{
+			name:    "monthly on the last Friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: utcDate(2024, 1, 26, 9),
+			want:    []time.Time{utcDate(2024, 1, 26, 9), utcDate(2024, 2, 23, 9), utcDate(2024, 3, 29, 9), utcDate(2024, 4, 26, 9)},
+		},
+		{
+			name:    "monthly on the fifth Monday skips months without one",
+			rule:    "FREQ=MONTHLY;BYDAY=5MO;COUNT=3",
+			dtstart: utcDate(2024, 1, 29, 9),
+			want:    []time.Time{utcDate(2024, 1, 29, 9), utcDate(2024, 4, 29, 9), utcDate(2024, 7, 29, 9)},
+		},
+		{
+			name:    "monthly on every Monday",
+			rule:    "FREQ=MONTHLY;BYDAY=MO;COUNT=6",
+			dtstart: utcDate(2024, 1, 22, 9),
+			want: []time.Time{
+				utcDate(2024, 1, 22, 9), utcDate(2024, 1, 29, 9), utcDate(2024, 2, 5, 9),
+				utcDate(2024, 2, 12, 9), utcDate(2024, 2, 19, 9), utcDate(2024, 2, 26, 9),
+			},
+		},
+		{
+			name:    "monthly on the 31st skips short months",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=31",
+			dtstart: utcDate(2024, 1, 31, 9),
+			want:    []time.Time{utcDate(2024, 1, 31, 9), utcDate(2024, 3, 31, 9), utcDate(2024, 5, 31, 9), utcDate(2024, 7, 31, 9), utcDate(2024, 8, 31, 9)},
+		},
+		{
+			name:    "monthly without BYMONTHDAY uses the day of dtstart",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: utcDate(2024, 1, 31, 9),
+			want:    []time.Time{utcDate(2024, 1, 31, 9), utcDate(2024, 3, 31, 9), utcDate(2024, 5, 31, 9)},
+		},
+		{
+			name:    "monthly on the last day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: utcDate(2023, 12, 31, 9),
+			want:    []time.Time{utcDate(2023, 12, 31, 9), utcDate(2024, 1, 31, 9), utcDate(2024, 2, 29, 9), utcDate(2024, 3, 31, 9), utcDate(2024, 4, 30, 9)},
+		},
+		{
+			name:    "monthly on Friday the 13th",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR;COUNT=3",
+			dtstart: utcDate(2024, 9, 13, 9),
+			want:    []time.Time{utcDate(2024, 9, 13, 9), utcDate(2024, 12, 13, 9), utcDate(2025, 6, 13, 9)},
+		},
+		{
+			name:    "quarterly with count",
+			rule:    "FREQ=MONTHLY;INTERVAL=3;COUNT=3",
+			dtstart: utcDate(2024, 1, 15, 9),
+			want:    []time.Time{utcDate(2024, 1, 15, 9), utcDate(2024, 4, 15, 9), utcDate(2024, 7, 15, 9)},
+		},
+		{
+			name:    "every other month on two days until a date",
+			rule:    "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,15;UNTIL=20240601",
+			dtstart: utcDate(2024, 1, 15, 10),
+			want:    []time.Time{utcDate(2024, 1, 15, 10), utcDate(2024, 3, 1, 10), utcDate(2024, 3, 15, 10), utcDate(2024, 5, 1, 10), utcDate(2024, 5, 15, 10)},
+		},
+		{
+			name:    "count of one ends after dtstart",
+			rule:    "FREQ=DAILY;COUNT=1",
+			dtstart: utcDate(2024, 1, 1, 9),
+			want:    []time.Time{utcDate(2024, 1, 1, 9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule: %v", err)
+			}
+			got := occurrences(rule, tt.dtstart, len(tt.want)+1)
+			if rule.Count == 0 && rule.Until == nil {
+				// Unbounded series: only compare the expected prefix
+				got = got[:len(tt.want)]
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range tt.want {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRRuleNext(t *testing.T) {
+	dtstart := utcDate(2024, 1, 1, 9)
+	rule, err := ParseRRule("FREQ=DAILY;COUNT=5")
+	if err != nil {
+		t.Fatalf("ParseRRule: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		after  time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{"before dtstart returns dtstart", utcDate(2023, 12, 1, 0), dtstart, true},
+		{"at dtstart returns the second occurrence", dtstart, utcDate(2024, 1, 2, 9), true},
+		{"between occurrences", utcDate(2024, 1, 3, 12), utcDate(2024, 1, 4, 9), true},
+		{"at the last occurrence", utcDate(2024, 1, 5, 9), time.Time{}, false},
+		{"long after the series ended", utcDate(2030, 1, 1, 0), time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rule.Next(dtstart, tt.after)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, %v; want %s, %v", tt.after, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRRuleNextKeepsLocation(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	dtstart := time.Date(2024, 1, 31, 8, 30, 0, 0, berlin)
+	rule, err := ParseRRule("FREQ=MONTHLY;BYMONTHDAY=-1")
+	if err != nil {
+		t.Fatalf("ParseRRule: %v", err)
+	}
+
+	got, ok := rule.Next(dtstart, dtstart)
+	want := time.Date(2024, 2, 29, 8, 30, 0, 0, berlin)
+	if !ok || !got.Equal(want) || got.Location() != berlin {
+		t.Errorf("Next = %s, %v; want %s", got, ok, want)
+	}
+}
+
+// TestRRuleNextNeverMatching checks that rules which can never match again end instead of
+// searching forever
+func TestRRuleNextNeverMatching(t *testing.T) {
+	tests := []string{
+		"FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=5MO", // the fifth Monday is never on the 1st
+		"FREQ=MONTHLY;BYMONTHDAY=-31;BYDAY=-1SU",
+	}
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			rule, err := ParseRRule(value)
+			if err != nil {
+				t.Fatalf("ParseRRule: %v", err)
+			}
+			dtstart := utcDate(2024, 1, 1, 9)
+
+			done := make(chan struct{})
+			var ok bool
+			go func() {
+				defer close(done)
+				_, ok = rule.Next(dtstart, dtstart)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("Next did not return")
+			}
+			if ok {
+				t.Error("Next found an occurrence for a rule that never matches")
+			}
+		})
+	}
+}